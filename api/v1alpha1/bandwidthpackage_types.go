@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BandwidthPackageSpec defines the desired state of BandwidthPackage
+type BandwidthPackageSpec struct {
+	// BandwidthPackageID 指定已存在的共享带宽包ID，如果指定则不会创建新的带宽包
+	// +optional
+	BandwidthPackageID string `json:"bandwidthPackageID,omitempty"`
+
+	// RegionID 共享带宽包所在地域，默认为控制器配置的地域，创建后不可修改
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="regionID is immutable"
+	// +optional
+	RegionID string `json:"regionID,omitempty"`
+
+	// CloudAccountName 管理该共享带宽包使用的CloudAccount，默认取命名空间注解eip.alibabacloud.com/cloud-account，
+	// 都未设置时使用控制器自身的凭证，创建后不可修改
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="cloudAccountName is immutable"
+	// +optional
+	CloudAccountName string `json:"cloudAccountName,omitempty"`
+
+	// Bandwidth 共享带宽包带宽，单位Mbps
+	// +kubebuilder:validation:Minimum=1
+	Bandwidth int32 `json:"bandwidth"`
+
+	// InternetChargeType 计费方式，支持PayByBandwidth、PayBy95和PayByDominantTraffic
+	// +kubebuilder:validation:Enum=PayByBandwidth;PayBy95;PayByDominantTraffic
+	// +kubebuilder:default:=PayByBandwidth
+	// +optional
+	InternetChargeType string `json:"internetChargeType,omitempty"`
+
+	// ISP 线路类型
+	// +optional
+	ISP string `json:"isp,omitempty"`
+
+	// ResourceGroupID 资源组ID
+	// +optional
+	ResourceGroupID string `json:"resourceGroupID,omitempty"`
+
+	// Name 共享带宽包名称
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Description 共享带宽包描述
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// SecurityProtectionTypes 安全防护类型
+	// +optional
+	SecurityProtectionTypes []string `json:"securityProtectionTypes,omitempty"`
+
+	// ReleaseStrategy 共享带宽包释放策略，默认OnDeleteIfCreated，通过bandwidthPackageID导入的共享带宽包不会被释放
+	// +kubebuilder:validation:Enum=Never;OnDelete;OnDeleteIfCreated
+	// +kubebuilder:default:=OnDeleteIfCreated
+	// +optional
+	ReleaseStrategy ReleaseStrategy `json:"releaseStrategy,omitempty"`
+}
+
+// BandwidthPackageMember 共享带宽包中的EIP
+type BandwidthPackageMember struct {
+	// AllocationID EIP实例ID
+	AllocationID string `json:"allocationID"`
+
+	// EIPAddress EIP地址
+	EIPAddress string `json:"eipAddress,omitempty"`
+
+	// EIPName 同命名空间下对应的EIP资源名称，未被本集群管理时为空
+	// +optional
+	EIPName string `json:"eipName,omitempty"`
+}
+
+// BandwidthPackageStatus defines the observed state of BandwidthPackage
+type BandwidthPackageStatus struct {
+	// BandwidthPackageID 共享带宽包ID
+	BandwidthPackageID string `json:"bandwidthPackageID,omitempty"`
+
+	// RegionID 共享带宽包所在地域
+	RegionID string `json:"regionID,omitempty"`
+
+	// CloudAccountName 共享带宽包所属的CloudAccount，为空表示使用控制器自身的凭证
+	CloudAccountName string `json:"cloudAccountName,omitempty"`
+
+	// Provenance 共享带宽包来源，Created或Imported，确定后不再改变
+	// +optional
+	Provenance EIPProvenance `json:"provenance,omitempty"`
+
+	// Status 共享带宽包状态
+	Status string `json:"status,omitempty"`
+
+	// Bandwidth 共享带宽包当前生效的带宽，单位Mbps
+	Bandwidth string `json:"bandwidth,omitempty"`
+
+	// InternetChargeType 计费方式
+	InternetChargeType string `json:"internetChargeType,omitempty"`
+
+	// ISP 线路类型
+	ISP string `json:"isp,omitempty"`
+
+	// Members 加入共享带宽包的EIP
+	Members []BandwidthPackageMember `json:"members,omitempty"`
+
+	// MemberCount 加入共享带宽包的EIP数量
+	MemberCount int32 `json:"memberCount"`
+
+	// Conditions 共享带宽包状态条件
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastSyncTime 最后同步时间
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=cbwp
+//+kubebuilder:printcolumn:name="BandwidthPackageID",type=string,JSONPath=`.status.bandwidthPackageID`
+//+kubebuilder:printcolumn:name="Bandwidth",type=string,JSONPath=`.status.bandwidth`
+//+kubebuilder:printcolumn:name="Members",type=integer,JSONPath=`.status.memberCount`
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
+//+kubebuilder:printcolumn:name="Provenance",type=string,JSONPath=`.status.provenance`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// BandwidthPackage is the Schema for the bandwidthpackages API
+type BandwidthPackage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BandwidthPackageSpec   `json:"spec,omitempty"`
+	Status BandwidthPackageStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// BandwidthPackageList contains a list of BandwidthPackage
+type BandwidthPackageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BandwidthPackage `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BandwidthPackage{}, &BandwidthPackageList{})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AnnotationCloudAccount 命名空间上的注解，该命名空间中的EIP默认使用指定的CloudAccount
+	AnnotationCloudAccount = "eip.alibabacloud.com/cloud-account"
+
+	// CloudAccountSecretKeyAccessKeyID Secret中AccessKey ID的键
+	CloudAccountSecretKeyAccessKeyID = "accessKeyID"
+	// CloudAccountSecretKeyAccessKeySecret Secret中AccessKey Secret的键
+	CloudAccountSecretKeyAccessKeySecret = "accessKeySecret"
+)
+
+// SecretReference 引用任意命名空间中的Secret
+type SecretReference struct {
+	// Namespace Secret所在命名空间
+	Namespace string `json:"namespace"`
+
+	// Name Secret名称
+	Name string `json:"name"`
+}
+
+// CloudAccountSpec defines the desired state of CloudAccount
+// +kubebuilder:validation:XValidation:rule="has(self.secretRef) || has(self.roleARN)",message="secretRef or roleARN is required"
+type CloudAccountSpec struct {
+	// SecretRef 存放AccessKey的Secret，键为accessKeyID和accessKeySecret
+	// 未设置时使用operator自身的凭证扮演roleARN
+	// +optional
+	SecretRef *SecretReference `json:"secretRef,omitempty"`
+
+	// RoleARN 要扮演的RAM角色，如acs:ram::123456789:role/eip-operator
+	// +optional
+	RoleARN string `json:"roleARN,omitempty"`
+
+	// RoleSessionName 扮演角色时的会话名称，默认alibabacloud-eip-operator
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9.@_-]{2,64}$`
+	// +optional
+	RoleSessionName string `json:"roleSessionName,omitempty"`
+
+	// AllowedNamespaces 允许使用该账号的命名空间，为空时不限制
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// AllowsNamespace 判断命名空间是否可以使用该账号
+func (a *CloudAccount) AllowsNamespace(namespace string) bool {
+	if len(a.Spec.AllowedNamespaces) == 0 {
+		return true
+	}
+	for _, ns := range a.Spec.AllowedNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// CloudAccountStatus defines the observed state of CloudAccount
+type CloudAccountStatus struct {
+	// AccountID 凭证所属的阿里云账号ID
+	AccountID string `json:"accountID,omitempty"`
+
+	// Arn 凭证对应的身份
+	Arn string `json:"arn,omitempty"`
+
+	// IdentityType 身份类型，如RAMUser、AssumedRoleUser
+	IdentityType string `json:"identityType,omitempty"`
+
+	// EIPCount 使用该账号的EIP数量
+	EIPCount int32 `json:"eipCount"`
+
+	// Conditions 账号状态条件，Ready表示凭证是否有效
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastValidatedTime 最后一次校验凭证的时间
+	LastValidatedTime *metav1.Time `json:"lastValidatedTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster,shortName=cloudacct
+//+kubebuilder:printcolumn:name="AccountID",type=string,JSONPath=`.status.accountID`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="EIPs",type=integer,JSONPath=`.status.eipCount`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// CloudAccount is the Schema for the cloudaccounts API
+type CloudAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudAccountSpec   `json:"spec,omitempty"`
+	Status CloudAccountStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CloudAccountList contains a list of CloudAccount
+type CloudAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudAccount `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CloudAccount{}, &CloudAccountList{})
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// FieldMutability 定义字段在创建后的变更方式
+type FieldMutability string
+
+const (
+	// FieldImmutable 字段标识云上资源本身，创建后不能修改
+	FieldImmutable FieldMutability = "Immutable"
+	// FieldMutableInPlace 字段可以原地修改，控制器会同步到云上
+	FieldMutableInPlace FieldMutability = "MutableInPlace"
+	// FieldReplacement 字段无法原地修改，只能删除并重建EIP
+	FieldReplacement FieldMutability = "Replacement"
+)
+
+// EIPSpecField 描述EIPSpec中一个字段的变更方式
+// +kubebuilder:object:generate=false
+type EIPSpecField struct {
+	// Name 字段的JSON名称
+	Name string
+	// Mutability 字段的变更方式
+	Mutability FieldMutability
+	// Get 返回字段在spec中的值
+	Get func(spec *EIPSpec) interface{}
+	// Observed 返回字段在status中观测到的云上值，无法观测的字段为nil
+	Observed func(status *EIPStatus) string
+}
+
+// EIPSpecFields 是EIPSpec全部字段的变更方式分类表，webhook和控制器都以此为准
+var EIPSpecFields = []EIPSpecField{
+	{
+		Name:       "allocationID",
+		Mutability: FieldImmutable,
+		Get:        func(spec *EIPSpec) interface{} { return spec.AllocationID },
+	},
+	{
+		Name:       "regionID",
+		Mutability: FieldImmutable,
+		Get:        func(spec *EIPSpec) interface{} { return spec.RegionID },
+	},
+	{
+		Name:       "cloudAccountName",
+		Mutability: FieldImmutable,
+		Get:        func(spec *EIPSpec) interface{} { return spec.CloudAccountName },
+	},
+	{
+		Name:       "importFrom",
+		Mutability: FieldImmutable,
+		Get:        func(spec *EIPSpec) interface{} { return spec.ImportFrom },
+	},
+	{
+		Name:       "bandwidth",
+		Mutability: FieldMutableInPlace,
+		Get:        func(spec *EIPSpec) interface{} { return spec.Bandwidth },
+		Observed:   func(status *EIPStatus) string { return status.Bandwidth },
+	},
+	{
+		Name:       "internetChargeType",
+		Mutability: FieldReplacement,
+		Get:        func(spec *EIPSpec) interface{} { return spec.InternetChargeType },
+		Observed:   func(status *EIPStatus) string { return status.InternetChargeType },
+	},
+	{
+		Name:       "instanceChargeType",
+		Mutability: FieldReplacement,
+		Get:        func(spec *EIPSpec) interface{} { return spec.InstanceChargeType },
+		Observed:   func(status *EIPStatus) string { return status.InstanceChargeType },
+	},
+	{
+		Name:       "isp",
+		Mutability: FieldReplacement,
+		Get:        func(spec *EIPSpec) interface{} { return spec.ISP },
+		Observed:   func(status *EIPStatus) string { return status.ISP },
+	},
+	{
+		Name:       "publicIPAddressPoolID",
+		Mutability: FieldReplacement,
+		Get:        func(spec *EIPSpec) interface{} { return spec.PublicIPAddressPoolID },
+		Observed:   func(status *EIPStatus) string { return status.PublicIPAddressPoolID },
+	},
+	{
+		Name:       "resourceGroupID",
+		Mutability: FieldReplacement,
+		Get:        func(spec *EIPSpec) interface{} { return spec.ResourceGroupID },
+		Observed:   func(status *EIPStatus) string { return status.ResourceGroupID },
+	},
+	{
+		Name:       "name",
+		Mutability: FieldMutableInPlace,
+		Get:        func(spec *EIPSpec) interface{} { return spec.Name },
+		Observed:   func(status *EIPStatus) string { return status.Name },
+	},
+	{
+		Name:       "description",
+		Mutability: FieldMutableInPlace,
+		Get:        func(spec *EIPSpec) interface{} { return spec.Description },
+		Observed:   func(status *EIPStatus) string { return status.Description },
+	},
+	{
+		Name:       "securityProtectionTypes",
+		Mutability: FieldReplacement,
+		Get:        func(spec *EIPSpec) interface{} { return spec.SecurityProtectionTypes },
+	},
+	{
+		Name:       "tags",
+		Mutability: FieldMutableInPlace,
+		Get:        func(spec *EIPSpec) interface{} { return spec.Tags },
+	},
+	{
+		Name:       "bandwidthPackageID",
+		Mutability: FieldMutableInPlace,
+		Get:        func(spec *EIPSpec) interface{} { return spec.BandwidthPackageID },
+	},
+	{
+		Name:       "bandwidthPackageName",
+		Mutability: FieldMutableInPlace,
+		Get:        func(spec *EIPSpec) interface{} { return spec.BandwidthPackageName },
+	},
+	{
+		Name:       "releaseStrategy",
+		Mutability: FieldMutableInPlace,
+		Get:        func(spec *EIPSpec) interface{} { return spec.ReleaseStrategy },
+	},
+	{
+		Name:       "deletionProtection",
+		Mutability: FieldMutableInPlace,
+		Get:        func(spec *EIPSpec) interface{} { return spec.DeletionProtection },
+	},
+	{
+		Name:       "deletionPolicy",
+		Mutability: FieldMutableInPlace,
+		Get:        func(spec *EIPSpec) interface{} { return spec.DeletionPolicy },
+	},
+}
@@ -17,17 +17,45 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
+	"net"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/chrisliu1995/alibabacloud-eip-operator/pkg/limits"
+)
+
+const (
+	// DefaultInternetChargeType 未指定时的计费方式
+	DefaultInternetChargeType = "PayByTraffic"
+	// DefaultInstanceChargeType 未指定时的实例计费方式
+	DefaultInstanceChargeType = "PostPaid"
+	// DefaultEIPDescription 未指定时的EIP描述
+	DefaultEIPDescription = "created by alibabacloud-eip-operator"
+
+	// AnnotationDeletionPolicyOverride 紧急情况下覆盖spec.deletionPolicy，取值为DisassociateThenRelease或Orphan，
+	// 需在删除前设置
+	AnnotationDeletionPolicyOverride = "eip.alibabacloud.com/deletion-policy-override"
+
+	// AnnotationManagedTags 记录由operator管理的云上标签键，未记录的标签不会被移除
+	AnnotationManagedTags = "eip.alibabacloud.com/managed-tags"
+
+	// AnnotationForceOwnership 设置为"true"时允许导入或释放归属其他集群的EIP
+	AnnotationForceOwnership = "eip.alibabacloud.com/force-ownership"
+
+	// EIPAllocationIDField 按引用的云上EIP ID索引EIP，由控制器注册，webhook用于拒绝重复引用
+	EIPAllocationIDField = ".spec.allocationID"
 )
 
 // EIPSpec defines the desired state of EIP
@@ -36,6 +64,20 @@ type EIPSpec struct {
 	// +optional
 	AllocationID string `json:"allocationID,omitempty"`
 
+	// RegionID EIP所在地域，默认为控制器配置的地域，创建后不可修改
+	// +optional
+	RegionID string `json:"regionID,omitempty"`
+
+	// CloudAccountName 管理该EIP使用的CloudAccount，默认取命名空间注解eip.alibabacloud.com/cloud-account，
+	// 都未设置时使用控制器自身的凭证，创建后不可修改
+	// +optional
+	CloudAccountName string `json:"cloudAccountName,omitempty"`
+
+	// ImportFrom 按公网IP或云上标签查找并导入已有的EIP，与allocationID二选一
+	// 解析出的AllocationID记录在status中，之后不再重新查找
+	// +optional
+	ImportFrom *EIPImportSource `json:"importFrom,omitempty"`
+
 	// Bandwidth EIP带宽，单位Mbps
 	// +optional
 	Bandwidth string `json:"bandwidth,omitempty"`
@@ -73,7 +115,7 @@ type EIPSpec struct {
 	// +optional
 	SecurityProtectionTypes []string `json:"securityProtectionTypes,omitempty"`
 
-	// Tags EIP标签
+	// Tags EIP标签，修改后会同步到云上，移除的标签会从云上删除
 	// +optional
 	Tags map[string]string `json:"tags,omitempty"`
 
@@ -81,21 +123,70 @@ type EIPSpec struct {
 	// +optional
 	BandwidthPackageID string `json:"bandwidthPackageID,omitempty"`
 
-	// ReleaseStrategy EIP释放策略
-	// +kubebuilder:validation:Enum=Never;OnDelete
-	// +kubebuilder:default:=OnDelete
+	// BandwidthPackageName 同命名空间下BandwidthPackage资源的名称，与bandwidthPackageID二选一
+	// +optional
+	BandwidthPackageName string `json:"bandwidthPackageName,omitempty"`
+
+	// ReleaseStrategy EIP释放策略，默认OnDeleteIfCreated，导入的EIP不会被释放
+	// +kubebuilder:validation:Enum=Never;OnDelete;OnDeleteIfCreated
+	// +kubebuilder:default:=OnDeleteIfCreated
 	ReleaseStrategy ReleaseStrategy `json:"releaseStrategy,omitempty"`
+
+	// DeletionProtection 云上删除保护，true时开启并保持开启，false时保持关闭，不设置时不管理
+	// 按ReleaseStrategy释放EIP前会先关闭删除保护
+	// +optional
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
+
+	// DeletionPolicy 删除CR时EIP仍绑定在实例上的处理方式，仅在按ReleaseStrategy需要释放EIP时生效
+	// +kubebuilder:default:=Block
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
-// ReleaseStrategy 定义EIP释放策略
-// +kubebuilder:validation:Enum=Never;OnDelete
+// EIPImportSource 查找要导入的已有EIP，ipAddress与tagSelector必须且只能指定一个
+type EIPImportSource struct {
+	// IPAddress 要导入的EIP公网IP地址
+	// +optional
+	IPAddress string `json:"ipAddress,omitempty"`
+
+	// TagSelector 按云上标签查找EIP，需要同时匹配全部标签，且只能匹配到一个EIP
+	// +optional
+	TagSelector map[string]string `json:"tagSelector,omitempty"`
+}
+
+// DeletionPolicy 定义删除仍在使用中的EIP时的处理方式
+// +kubebuilder:validation:Enum=Block;DisassociateThenRelease;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyBlock EIP仍绑定实例时拒绝删除，finalizer等待解绑后再释放
+	DeletionPolicyBlock DeletionPolicy = "Block"
+	// DeletionPolicyDisassociateThenRelease 先解绑EIP再释放
+	DeletionPolicyDisassociateThenRelease DeletionPolicy = "DisassociateThenRelease"
+	// DeletionPolicyOrphan 只删除CR，保留云上EIP及其绑定关系
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// ReleaseStrategy 定义云上资源释放策略，各资源在字段上声明可用的取值
 type ReleaseStrategy string
 
 const (
-	// ReleaseStrategyNever 永不释放EIP，即使删除CR也不释放
+	// ReleaseStrategyNever 永不释放云上资源，即使删除CR也不释放
 	ReleaseStrategyNever ReleaseStrategy = "Never"
-	// ReleaseStrategyOnDelete 删除CR时释放EIP
+	// ReleaseStrategyOnDelete 删除CR时释放云上资源，包括通过ID导入的资源
 	ReleaseStrategyOnDelete ReleaseStrategy = "OnDelete"
+	// ReleaseStrategyOnDeleteIfCreated 删除CR时只释放由operator创建的资源，导入的资源保留
+	ReleaseStrategyOnDeleteIfCreated ReleaseStrategy = "OnDeleteIfCreated"
+)
+
+// EIPProvenance EIP及共享带宽包、地址池、地址段等云上资源的来源
+type EIPProvenance string
+
+const (
+	// EIPProvenanceCreated 资源由operator为该CR创建
+	EIPProvenanceCreated EIPProvenance = "Created"
+	// EIPProvenanceImported 资源在云上已存在，通过ID导入
+	EIPProvenanceImported EIPProvenance = "Imported"
 )
 
 // EIPStatus defines the observed state of EIP
@@ -106,6 +197,12 @@ type EIPStatus struct {
 	// EIPAddress EIP地址
 	EIPAddress string `json:"eipAddress,omitempty"`
 
+	// RegionID EIP所在地域
+	RegionID string `json:"regionID,omitempty"`
+
+	// CloudAccountName EIP所属的CloudAccount，为空表示使用控制器自身的凭证
+	CloudAccountName string `json:"cloudAccountName,omitempty"`
+
 	// Status EIP状态
 	Status string `json:"status,omitempty"`
 
@@ -136,6 +233,25 @@ type EIPStatus struct {
 	// Description EIP描述
 	Description string `json:"description,omitempty"`
 
+	// InstanceID EIP当前绑定的实例ID
+	InstanceID string `json:"instanceID,omitempty"`
+
+	// InstanceType EIP当前绑定的实例类型
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// PrivateIPAddress EIP当前绑定的私网IP
+	PrivateIPAddress string `json:"privateIPAddress,omitempty"`
+
+	// DeletionProtection 云上观测到的删除保护状态
+	DeletionProtection bool `json:"deletionProtection"`
+
+	// Provenance EIP来源，Created或Imported，确定后不再改变
+	// +optional
+	Provenance EIPProvenance `json:"provenance,omitempty"`
+
+	// Tags 云上观测到的EIP标签，包括非operator添加的标签
+	Tags map[string]string `json:"tags,omitempty"`
+
 	// Conditions EIP状态条件
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
@@ -145,11 +261,13 @@ type EIPStatus struct {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 //+kubebuilder:resource:shortName=eip
 //+kubebuilder:printcolumn:name="AllocationID",type=string,JSONPath=`.status.allocationID`
 //+kubebuilder:printcolumn:name="EIP Address",type=string,JSONPath=`.status.eipAddress`
 //+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
 //+kubebuilder:printcolumn:name="Bandwidth",type=string,JSONPath=`.status.bandwidth`
+//+kubebuilder:printcolumn:name="Provenance",type=string,JSONPath=`.status.provenance`,priority=1
 //+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // EIP is the Schema for the eips API
@@ -176,51 +294,289 @@ func init() {
 
 var eiplog = logf.Log.WithName("eip-resource")
 
+// eipWebhookReader 用于在校验时读取集群内的其他资源，未设置时跳过相关校验
+var eipWebhookReader client.Reader
+
+// EIPCloudValidator 在准入时通过云上接口校验EIP引用的资源，old为nil表示创建
+// +kubebuilder:object:generate=false
+type EIPCloudValidator interface {
+	ValidateEIP(ctx context.Context, eip, old *EIP) (admission.Warnings, field.ErrorList)
+}
+
+// eipCloudValidator 可选的云上资源校验，未设置时只做静态校验
+var eipCloudValidator EIPCloudValidator
+
+// SetEIPCloudValidator 设置准入时使用的云上资源校验
+func SetEIPCloudValidator(v EIPCloudValidator) {
+	eipCloudValidator = v
+}
+
+// eipBandwidthLimits 带宽限制表，校验时按EIP所在地域取生效的规则，未设置时使用内置限制表
+var eipBandwidthLimits = limits.Default()
+
+// SetBandwidthLimits 设置校验时使用的带宽限制表
+func SetBandwidthLimits(t *limits.Table) {
+	eipBandwidthLimits = t
+}
+
+// eipDefaultRegionID 未指定spec.regionID时使用的地域
+var eipDefaultRegionID string
+
+// SetDefaultRegionID 设置spec.regionID的默认值，应与控制器配置的地域一致
+func SetDefaultRegionID(regionID string) {
+	eipDefaultRegionID = regionID
+}
+
+// regionOrDefault 地域为空时返回默认地域
+func regionOrDefault(regionID string) string {
+	if regionID == "" {
+		return eipDefaultRegionID
+	}
+	return regionID
+}
+
 // SetupWebhookWithManager sets up the webhook with the Manager.
 func (r *EIP) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	eipWebhookReader = mgr.GetClient()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
 }
 
-//+kubebuilder:webhook:path=/validate-eip-alibabacloud-com-v1alpha1-eip,mutating=false,failurePolicy=fail,sideEffects=None,groups=eip.alibabacloud.com,resources=eips,verbs=create;update,versions=v1alpha1,name=veip.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/mutate-eip-alibabacloud-com-v1alpha1-eip,mutating=true,failurePolicy=fail,sideEffects=None,groups=eip.alibabacloud.com,resources=eips,verbs=create;update,versions=v1alpha1,name=meip.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &EIP{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+// 仅在云上EIP创建前填充默认值，已创建或导入的EIP保持原样，避免修改云上已有属性
+func (r *EIP) Default() {
+	eiplog.Info("default", "name", r.Name)
+
+	if r.Spec.ReleaseStrategy == "" {
+		r.Spec.ReleaseStrategy = ReleaseStrategyOnDeleteIfCreated
+	}
+	if r.Spec.DeletionPolicy == "" {
+		r.Spec.DeletionPolicy = DeletionPolicyBlock
+	}
+	// 已同步的EIP沿用实际所在地域，避免默认地域调整后被误判为修改地域
+	if r.Spec.RegionID == "" {
+		r.Spec.RegionID = regionOrDefault(r.Status.RegionID)
+	}
+	if r.Spec.AllocationID != "" || r.Spec.ImportFrom != nil || r.Status.AllocationID != "" {
+		return
+	}
+
+	if r.Spec.InternetChargeType == "" {
+		r.Spec.InternetChargeType = DefaultInternetChargeType
+	}
+	if r.Spec.InstanceChargeType == "" {
+		r.Spec.InstanceChargeType = DefaultInstanceChargeType
+	}
+	if r.Spec.Description == "" {
+		r.Spec.Description = DefaultEIPDescription
+	}
+	// 使用generateName创建时名称尚未生成，由控制器创建EIP时再补充
+	if r.Spec.Name == "" && r.Name != "" {
+		r.Spec.Name = defaultCloudName(r.Namespace, r.Name)
+	}
+}
+
+// defaultCloudName 生成云上EIP名称，阿里云要求以字母开头且长度不超过128
+func defaultCloudName(namespace, name string) string {
+	cloudName := fmt.Sprintf("k8s-%s-%s", namespace, name)
+	if len(cloudName) > 128 {
+		cloudName = cloudName[:128]
+	}
+	return cloudName
+}
+
+//+kubebuilder:webhook:path=/validate-eip-alibabacloud-com-v1alpha1-eip,mutating=false,failurePolicy=fail,sideEffects=None,groups=eip.alibabacloud.com,resources=eips,verbs=create;update;delete,versions=v1alpha1,name=veip.kb.io,admissionReviewVersions=v1
 
 var _ webhook.Validator = &EIP{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *EIP) ValidateCreate() (admission.Warnings, error) {
 	eiplog.Info("validate create", "name", r.Name)
-	return nil, r.validateEIP()
+	if err := r.validateImportFrom(); err != nil {
+		return nil, apierrors.NewInvalid(
+			schema.GroupKind{Group: "eip.alibabacloud.com", Kind: "EIP"},
+			r.Name,
+			field.ErrorList{err},
+		)
+	}
+	if err := r.validateEIP(nil); err != nil {
+		return nil, err
+	}
+	if err := r.validateAllocationIDUnique(nil); err != nil {
+		return nil, apierrors.NewInvalid(
+			schema.GroupKind{Group: "eip.alibabacloud.com", Kind: "EIP"},
+			r.Name,
+			field.ErrorList{err},
+		)
+	}
+	if err := r.validateCloudAccount(); err != nil {
+		return nil, apierrors.NewInvalid(
+			schema.GroupKind{Group: "eip.alibabacloud.com", Kind: "EIP"},
+			r.Name,
+			field.ErrorList{err},
+		)
+	}
+
+	// 校验地址池容量，仅在创建新的EIP时需要
+	if err := r.validatePublicIPAddressPoolCapacity(); err != nil {
+		return nil, apierrors.NewInvalid(
+			schema.GroupKind{Group: "eip.alibabacloud.com", Kind: "EIP"},
+			r.Name,
+			field.ErrorList{err},
+		)
+	}
+
+	warnings, err := r.validateCloudResources(nil)
+	if (r.Spec.AllocationID != "" || r.Spec.ImportFrom != nil) && r.Spec.ReleaseStrategy == ReleaseStrategyOnDelete {
+		warnings = append(warnings, "releaseStrategy 为 OnDelete，删除 CR 时会释放导入的 EIP；"+
+			"如需保留请使用 OnDeleteIfCreated 或 Never")
+	}
+	return warnings, err
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *EIP) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	eiplog.Info("validate update", "name", r.Name)
-	return nil, r.validateEIP()
+	oldEIP, ok := old.(*EIP)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an EIP but got a %T", old))
+	}
+
+	// 校验不可修改的字段
+	if allErrs := r.validateSpecUpdate(oldEIP); len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(
+			schema.GroupKind{Group: "eip.alibabacloud.com", Kind: "EIP"},
+			r.Name,
+			allErrs,
+		)
+	}
+	if err := r.validateEIP(oldEIP); err != nil {
+		return nil, err
+	}
+	if err := r.validateAllocationIDUnique(oldEIP); err != nil {
+		return nil, apierrors.NewInvalid(
+			schema.GroupKind{Group: "eip.alibabacloud.com", Kind: "EIP"},
+			r.Name,
+			field.ErrorList{err},
+		)
+	}
+	return r.validateCloudResources(oldEIP)
+}
+
+// validateCloudResources 调用可选的云上资源校验
+func (r *EIP) validateCloudResources(old *EIP) (admission.Warnings, error) {
+	if eipCloudValidator == nil {
+		return nil, nil
+	}
+
+	warnings, allErrs := eipCloudValidator.ValidateEIP(context.TODO(), r, old)
+	if len(allErrs) > 0 {
+		return warnings, apierrors.NewInvalid(
+			schema.GroupKind{Group: "eip.alibabacloud.com", Kind: "EIP"},
+			r.Name,
+			allErrs,
+		)
+	}
+	return warnings, nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
 func (r *EIP) ValidateDelete() (admission.Warnings, error) {
 	eiplog.Info("validate delete", "name", r.Name)
+
+	if override, ok := r.Annotations[AnnotationDeletionPolicyOverride]; ok {
+		if err := validateDeletionPolicyOverride(override); err != nil {
+			return nil, apierrors.NewForbidden(
+				schema.GroupResource{Group: "eip.alibabacloud.com", Resource: "eips"}, r.Name, err)
+		}
+		return admission.Warnings{fmt.Sprintf("deletionPolicy 被注解 %s 覆盖为 %s", AnnotationDeletionPolicyOverride, override)}, nil
+	}
+
+	// 需要释放且仍绑定实例的EIP按Block策略拒绝删除
+	if r.EffectiveDeletionPolicy() == DeletionPolicyBlock && r.WillBeReleased() && r.Status.InstanceID != "" {
+		return nil, apierrors.NewForbidden(
+			schema.GroupResource{Group: "eip.alibabacloud.com", Resource: "eips"}, r.Name,
+			fmt.Errorf("EIP %s 仍绑定在 %s %s 上，deletionPolicy 为 Block；请先解绑，或修改 deletionPolicy，"+
+				"紧急情况下可设置注解 %s=DisassociateThenRelease|Orphan 后再删除",
+				r.Status.AllocationID, r.Status.InstanceType, r.Status.InstanceID, AnnotationDeletionPolicyOverride))
+	}
+
 	return nil, nil
 }
 
+// AllocationIDs 返回EIP引用的云上EIP ID，spec和status中的ID都会计入
+func (r *EIP) AllocationIDs() []string {
+	switch {
+	case r.Spec.AllocationID == "" && r.Status.AllocationID == "":
+		return nil
+	case r.Spec.AllocationID == "" || r.Spec.AllocationID == r.Status.AllocationID:
+		return []string{r.Status.AllocationID}
+	case r.Status.AllocationID == "":
+		return []string{r.Spec.AllocationID}
+	default:
+		return []string{r.Spec.AllocationID, r.Status.AllocationID}
+	}
+}
+
+// WillBeReleased 删除CR时是否会按ReleaseStrategy释放云上EIP
+// OnDeleteIfCreated只释放确认由operator创建的EIP，来源未知时按导入处理
+func (r *EIP) WillBeReleased() bool {
+	if r.Status.AllocationID == "" {
+		return false
+	}
+	switch r.Spec.ReleaseStrategy {
+	case ReleaseStrategyOnDelete:
+		return true
+	case ReleaseStrategyOnDeleteIfCreated:
+		return r.Status.Provenance == EIPProvenanceCreated
+	default:
+		return false
+	}
+}
+
+// EffectiveDeletionPolicy 返回生效的删除策略，覆盖注解优先于spec.deletionPolicy
+func (r *EIP) EffectiveDeletionPolicy() DeletionPolicy {
+	if override, ok := r.Annotations[AnnotationDeletionPolicyOverride]; ok && validateDeletionPolicyOverride(override) == nil {
+		return DeletionPolicy(override)
+	}
+	if r.Spec.DeletionPolicy == "" {
+		return DeletionPolicyBlock
+	}
+	return r.Spec.DeletionPolicy
+}
+
+// validateDeletionPolicyOverride 覆盖注解只允许放宽为DisassociateThenRelease或Orphan
+func validateDeletionPolicyOverride(value string) error {
+	switch DeletionPolicy(value) {
+	case DeletionPolicyDisassociateThenRelease, DeletionPolicyOrphan:
+		return nil
+	}
+	return fmt.Errorf("注解 %s 的取值必须为 %s 或 %s，当前为 %q",
+		AnnotationDeletionPolicyOverride, DeletionPolicyDisassociateThenRelease, DeletionPolicyOrphan, value)
+}
+
 // validateEIP validates the EIP configuration
-func (r *EIP) validateEIP() error {
+// old为nil表示创建
+func (r *EIP) validateEIP(old *EIP) error {
 	var allErrs field.ErrorList
 
-	// 校验单线 EIP 必须使用按固定带宽付费
-	if err := r.validateSingleLineISP(); err != nil {
+	// 校验 ISP 与计费方式的组合以及带宽范围
+	if err := r.validateLimits(old); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
-	// 校验实例计费类型与流量计费类型的关系
-	if err := r.validateInstanceChargeType(); err != nil {
+	// 校验带宽值
+	if err := r.validateBandwidth(); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
-	// 校验带宽值
-	if err := r.validateBandwidth(); err != nil {
+	// 校验带宽包引用
+	if err := r.validateBandwidthPackage(); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
@@ -235,86 +591,181 @@ func (r *EIP) validateEIP() error {
 	)
 }
 
-// validateSingleLineISP 校验单线 ISP 不能使用包年包月，只能使用按带宽付费
-func (r *EIP) validateSingleLineISP() *field.Error {
-	// 单线 ISP 类型列表
-	singleLineISPs := map[string]bool{
-		"ChinaTelecom": true,
-		"ChinaUnicom":  true,
-		"ChinaMobile":  true,
-	}
-
-	// 如果使用单线 ISP
-	if r.Spec.ISP != "" && singleLineISPs[r.Spec.ISP] {
-		// 检查实例计费类型
-		instanceChargeType := r.Spec.InstanceChargeType
-		if instanceChargeType == "" {
-			instanceChargeType = "PostPaid" // 默认值
-		}
+// validateLimits 按带宽限制表校验 ISP 与计费方式的组合以及带宽范围
+// 更新时只在相关字段变化时校验，避免限制表调整后已有的EIP无法更新
+func (r *EIP) validateLimits(old *EIP) *field.Error {
+	if old != nil && old.Spec.ISP == r.Spec.ISP && old.Spec.Bandwidth == r.Spec.Bandwidth &&
+		old.Spec.InternetChargeType == r.Spec.InternetChargeType && old.Spec.InstanceChargeType == r.Spec.InstanceChargeType {
+		return nil
+	}
 
-		// 单线 ISP 不能使用包年包月（PrePaid）
-		if instanceChargeType == "PrePaid" {
-			return field.Invalid(
-				field.NewPath("spec").Child("instanceChargeType"),
-				instanceChargeType,
-				fmt.Sprintf("单线 ISP (%s) 不支持包年包月 (PrePaid)，只能使用后付费 (PostPaid)", r.Spec.ISP),
-			)
-		}
+	// 加入带宽包后带宽由带宽包决定
+	bandwidth := r.Spec.Bandwidth
+	if r.Spec.BandwidthPackageID != "" || r.Spec.BandwidthPackageName != "" {
+		bandwidth = ""
+	}
 
-		// 检查流量计费方式
-		internetChargeType := r.Spec.InternetChargeType
-		if internetChargeType == "" {
-			internetChargeType = "PayByTraffic" // 默认值
-		}
+	v := eipBandwidthLimits.ForRegion(regionOrDefault(r.Spec.RegionID)).Check(r.Spec.ISP, r.Spec.InternetChargeType, r.Spec.InstanceChargeType, bandwidth)
+	if v == nil {
+		return nil
+	}
+	return field.Invalid(field.NewPath("spec").Child(v.Field), v.Value, v.Message)
+}
 
-		// 单线 ISP 只能使用按带宽付费
-		if internetChargeType != "PayByBandwidth" {
-			return field.Invalid(
-				field.NewPath("spec").Child("internetChargeType"),
-				internetChargeType,
-				fmt.Sprintf("单线 ISP (%s) 只支持按固定带宽付费 (PayByBandwidth)，不支持按流量付费 (PayByTraffic)", r.Spec.ISP),
-			)
-		}
+// validateBandwidth 校验带宽值
+func (r *EIP) validateBandwidth() *field.Error {
+	// 如果使用按带宽付费，必须指定带宽
+	if r.Spec.InternetChargeType == "PayByBandwidth" && r.Spec.Bandwidth == "" {
+		return field.Required(
+			field.NewPath("spec").Child("bandwidth"),
+			"使用按带宽付费 (PayByBandwidth) 时必须指定带宽值",
+		)
+	}
+
+	return nil
+}
+
+// validateBandwidthPackage 校验带宽包ID与带宽包资源名称不能同时指定
+func (r *EIP) validateBandwidthPackage() *field.Error {
+	if r.Spec.BandwidthPackageID != "" && r.Spec.BandwidthPackageName != "" {
+		return field.Forbidden(
+			field.NewPath("spec").Child("bandwidthPackageName"),
+			"bandwidthPackageName 与 bandwidthPackageID 不能同时指定",
+		)
 	}
 
 	return nil
 }
 
-// validateInstanceChargeType 校验实例计费类型与流量计费类型的关系
-func (r *EIP) validateInstanceChargeType() *field.Error {
-	// 获取实例计费类型，默认为 PostPaid
-	instanceChargeType := r.Spec.InstanceChargeType
-	if instanceChargeType == "" {
-		instanceChargeType = "PostPaid"
+// validateImportFrom 校验导入来源，只在创建时校验，控制器回填allocationID后两者会同时存在
+func (r *EIP) validateImportFrom() *field.Error {
+	source := r.Spec.ImportFrom
+	if source == nil {
+		return nil
 	}
 
-	// 获取流量计费类型，默认为 PayByTraffic
-	internetChargeType := r.Spec.InternetChargeType
-	if internetChargeType == "" {
-		internetChargeType = "PayByTraffic"
+	path := field.NewPath("spec").Child("importFrom")
+	if r.Spec.AllocationID != "" {
+		return field.Forbidden(path, "importFrom 与 allocationID 不能同时指定")
+	}
+	if (source.IPAddress == "") == (len(source.TagSelector) == 0) {
+		return field.Invalid(path, source, "ipAddress 与 tagSelector 必须且只能指定一个")
+	}
+	if source.IPAddress != "" && net.ParseIP(source.IPAddress) == nil {
+		return field.Invalid(path.Child("ipAddress"), source.IPAddress, "不是合法的IP地址")
 	}
+	return nil
+}
 
-	// 当实例计费类型为 PrePaid（预付费）时，流量计费类型必须为 PayByBandwidth
-	if instanceChargeType == "PrePaid" && internetChargeType != "PayByBandwidth" {
-		return field.Invalid(
-			field.NewPath("spec").Child("internetChargeType"),
-			internetChargeType,
-			fmt.Sprintf("当 InstanceChargeType 为 PrePaid（预付费）时，InternetChargeType 必须为 PayByBandwidth，不能为 %s", internetChargeType),
+// validateAllocationIDUnique 拒绝引用已被其他EIP管理的云上EIP，包括其他命名空间中的EIP
+// 依赖控制器注册的EIPAllocationIDField索引，查询失败时不阻止请求，由控制器设置Conflict Condition
+func (r *EIP) validateAllocationIDUnique(old *EIP) *field.Error {
+	id := r.Spec.AllocationID
+	if eipWebhookReader == nil || id == "" || (old != nil && old.Spec.AllocationID == id) {
+		return nil
+	}
+
+	eips := &EIPList{}
+	if err := eipWebhookReader.List(context.TODO(), eips, client.MatchingFields{EIPAllocationIDField: id}); err != nil {
+		eiplog.Error(err, "failed to list EIPs by allocationID", "name", r.Name)
+		return nil
+	}
+
+	for _, other := range eips.Items {
+		if other.Namespace == r.Namespace && other.Name == r.Name {
+			continue
+		}
+		return field.Forbidden(
+			field.NewPath("spec").Child("allocationID"),
+			fmt.Sprintf("EIP %s 已被 %s/%s 管理，同一个云上 EIP 只能由一个 EIP 资源管理", id, other.Namespace, other.Name),
 		)
 	}
 
 	return nil
 }
 
-// validateBandwidth 校验带宽值
-func (r *EIP) validateBandwidth() *field.Error {
-	// 如果使用按带宽付费，必须指定带宽
-	if r.Spec.InternetChargeType == "PayByBandwidth" && r.Spec.Bandwidth == "" {
-		return field.Required(
-			field.NewPath("spec").Child("bandwidth"),
-			"使用按带宽付费 (PayByBandwidth) 时必须指定带宽值",
-		)
+// validateCloudAccount 校验spec.cloudAccountName引用的CloudAccount存在且允许当前命名空间使用
+func (r *EIP) validateCloudAccount() *field.Error {
+	name := r.Spec.CloudAccountName
+	if eipWebhookReader == nil || name == "" {
+		return nil
+	}
+
+	path := field.NewPath("spec").Child("cloudAccountName")
+	account := &CloudAccount{}
+	if err := eipWebhookReader.Get(context.TODO(), client.ObjectKey{Name: name}, account); err != nil {
+		if apierrors.IsNotFound(err) {
+			return field.NotFound(path, name)
+		}
+		// 无法获取时不阻止创建，由控制器在使用账号时再次校验
+		eiplog.Error(err, "failed to get CloudAccount", "name", r.Name, "cloudAccount", name)
+		return nil
+	}
+	if !account.AllowsNamespace(r.Namespace) {
+		return field.Forbidden(path, fmt.Sprintf("CloudAccount %s 不允许命名空间 %s 使用", name, r.Namespace))
+	}
+	return nil
+}
+
+// validatePublicIPAddressPoolCapacity 校验从地址池创建EIP时地址池中仍有可分配的IP
+func (r *EIP) validatePublicIPAddressPoolCapacity() *field.Error {
+	if eipWebhookReader == nil || r.Spec.AllocationID != "" || r.Spec.ImportFrom != nil || r.Spec.PublicIPAddressPoolID == "" {
+		return nil
+	}
+
+	pools := &PublicIPAddressPoolList{}
+	if err := eipWebhookReader.List(context.TODO(), pools); err != nil {
+		// 无法获取地址池信息时不阻止创建，由阿里云接口返回最终结果
+		eiplog.Error(err, "failed to list PublicIPAddressPools", "name", r.Name)
+		return nil
+	}
+
+	for _, pool := range pools.Items {
+		if pool.Status.PublicIPAddressPoolID != r.Spec.PublicIPAddressPoolID || pool.Status.LastSyncTime == nil {
+			continue
+		}
+		if pool.Status.Free <= 0 {
+			return field.Forbidden(
+				field.NewPath("spec").Child("publicIPAddressPoolID"),
+				fmt.Sprintf("公网IP地址池 %s (%s) 已没有可分配的IP", r.Spec.PublicIPAddressPoolID, pool.Name),
+			)
+		}
 	}
 
 	return nil
 }
+
+// validateSpecUpdate 按 EIPSpecFields 分类表校验修改的字段
+func (r *EIP) validateSpecUpdate(old *EIP) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// 旧对象可能创建于默认值填充之前，先按相同规则补齐再比较
+	old = old.DeepCopy()
+	old.Default()
+
+	for _, f := range EIPSpecFields {
+		oldValue, newValue := f.Get(&old.Spec), f.Get(&r.Spec)
+		if equality.Semantic.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		path := field.NewPath("spec").Child(f.Name)
+		switch f.Mutability {
+		case FieldImmutable:
+			// 控制器创建EIP后回填allocationID，此时取值与status一致
+			if f.Name == "allocationID" && old.Spec.AllocationID == "" && r.Spec.AllocationID == old.Status.AllocationID {
+				continue
+			}
+			// 早于regionID字段创建的EIP在更新时由默认值回填实际所在地域
+			if f.Name == "regionID" && old.Spec.RegionID == "" && r.Spec.RegionID == regionOrDefault(old.Status.RegionID) {
+				continue
+			}
+			allErrs = append(allErrs, field.Forbidden(path, fmt.Sprintf("%s 创建后不能修改", f.Name)))
+		case FieldReplacement:
+			allErrs = append(allErrs, field.Forbidden(path,
+				fmt.Sprintf("%s 无法原地修改，请删除并重新创建 EIP", f.Name)))
+		}
+	}
+
+	return allErrs
+}
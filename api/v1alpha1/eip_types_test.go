@@ -0,0 +1,581 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// withDefaultRegionID sets the default region for the duration of a test
+func withDefaultRegionID(t *testing.T, regionID string) {
+	previous := eipDefaultRegionID
+	SetDefaultRegionID(regionID)
+	t.Cleanup(func() { SetDefaultRegionID(previous) })
+}
+
+// syncedEIP returns an EIP the controller has already created in the cloud
+func syncedEIP() *EIP {
+	return &EIP{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: EIPSpec{
+			AllocationID:            "eip-123",
+			RegionID:                "cn-hangzhou",
+			CloudAccountName:        "tenant-a",
+			Bandwidth:               "10",
+			InternetChargeType:      "PayByTraffic",
+			InstanceChargeType:      "PostPaid",
+			ISP:                     "BGP",
+			PublicIPAddressPoolID:   "pippool-123",
+			ResourceGroupID:         "rg-123",
+			Name:                    "web",
+			Description:             "web eip",
+			SecurityProtectionTypes: []string{"AntiDDoS_Enhanced"},
+			Tags:                    map[string]string{"team": "net"},
+			ReleaseStrategy:         ReleaseStrategyOnDeleteIfCreated,
+			DeletionPolicy:          DeletionPolicyBlock,
+		},
+		Status: EIPStatus{
+			AllocationID: "eip-123",
+			RegionID:     "cn-hangzhou",
+		},
+	}
+}
+
+func TestValidateSpecUpdate(t *testing.T) {
+	withDefaultRegionID(t, "cn-hangzhou")
+
+	tests := []struct {
+		name string
+		old  func() *EIP
+		// update modifies the new object, the old object is left as returned by old
+		update    func(eip *EIP)
+		wantField string
+	}{
+		{
+			name:   "unchanged",
+			old:    syncedEIP,
+			update: func(eip *EIP) {},
+		},
+		{
+			name: "in place fields",
+			old:  syncedEIP,
+			update: func(eip *EIP) {
+				deletionProtection := true
+				eip.Spec.Bandwidth = "20"
+				eip.Spec.Name = "api"
+				eip.Spec.Description = "api eip"
+				eip.Spec.Tags = map[string]string{"team": "api"}
+				eip.Spec.BandwidthPackageID = "cbwp-123"
+				eip.Spec.ReleaseStrategy = ReleaseStrategyNever
+				eip.Spec.DeletionProtection = &deletionProtection
+				eip.Spec.DeletionPolicy = DeletionPolicyOrphan
+			},
+		},
+		{
+			name: "allocationID backfilled by the controller",
+			old: func() *EIP {
+				eip := syncedEIP()
+				eip.Spec.AllocationID = ""
+				return eip
+			},
+			update: func(eip *EIP) { eip.Spec.AllocationID = "eip-123" },
+		},
+		{
+			name: "regionID backfilled with the default",
+			old: func() *EIP {
+				eip := syncedEIP()
+				eip.Spec.RegionID = ""
+				eip.Status.RegionID = ""
+				return eip
+			},
+			update: func(eip *EIP) { eip.Spec.RegionID = "cn-hangzhou" },
+		},
+		{
+			name: "defaults filled after creation",
+			old: func() *EIP {
+				eip := syncedEIP()
+				eip.Spec.AllocationID = ""
+				eip.Status.AllocationID = ""
+				eip.Spec.InternetChargeType = ""
+				eip.Spec.InstanceChargeType = ""
+				return eip
+			},
+			// The mutating webhook fills the defaults on the new object
+			update: func(eip *EIP) { eip.Default() },
+		},
+		// Immutable rows of EIPSpecFields
+		{
+			name:      "allocationID",
+			old:       syncedEIP,
+			update:    func(eip *EIP) { eip.Spec.AllocationID = "eip-456" },
+			wantField: "spec.allocationID",
+		},
+		{
+			name:      "regionID",
+			old:       syncedEIP,
+			update:    func(eip *EIP) { eip.Spec.RegionID = "cn-beijing" },
+			wantField: "spec.regionID",
+		},
+		{
+			name: "regionID backfilled with another region",
+			old: func() *EIP {
+				eip := syncedEIP()
+				eip.Spec.RegionID = ""
+				eip.Status.RegionID = ""
+				return eip
+			},
+			update:    func(eip *EIP) { eip.Spec.RegionID = "cn-beijing" },
+			wantField: "spec.regionID",
+		},
+		{
+			name:      "cloudAccountName",
+			old:       syncedEIP,
+			update:    func(eip *EIP) { eip.Spec.CloudAccountName = "tenant-b" },
+			wantField: "spec.cloudAccountName",
+		},
+		{
+			name: "importFrom",
+			old: func() *EIP {
+				eip := syncedEIP()
+				eip.Spec.AllocationID = ""
+				eip.Spec.ImportFrom = &EIPImportSource{IPAddress: "47.0.0.1"}
+				return eip
+			},
+			update: func(eip *EIP) {
+				eip.Spec.AllocationID = ""
+				eip.Spec.ImportFrom = &EIPImportSource{IPAddress: "47.0.0.2"}
+			},
+			wantField: "spec.importFrom",
+		},
+		// Replacement rows of EIPSpecFields
+		{
+			name:      "internetChargeType",
+			old:       syncedEIP,
+			update:    func(eip *EIP) { eip.Spec.InternetChargeType = "PayByBandwidth" },
+			wantField: "spec.internetChargeType",
+		},
+		{
+			name:      "instanceChargeType",
+			old:       syncedEIP,
+			update:    func(eip *EIP) { eip.Spec.InstanceChargeType = "PrePaid" },
+			wantField: "spec.instanceChargeType",
+		},
+		{
+			name:      "isp",
+			old:       syncedEIP,
+			update:    func(eip *EIP) { eip.Spec.ISP = "BGP_PRO" },
+			wantField: "spec.isp",
+		},
+		{
+			name:      "publicIPAddressPoolID",
+			old:       syncedEIP,
+			update:    func(eip *EIP) { eip.Spec.PublicIPAddressPoolID = "pippool-456" },
+			wantField: "spec.publicIPAddressPoolID",
+		},
+		{
+			name:      "resourceGroupID",
+			old:       syncedEIP,
+			update:    func(eip *EIP) { eip.Spec.ResourceGroupID = "rg-456" },
+			wantField: "spec.resourceGroupID",
+		},
+		{
+			name:      "securityProtectionTypes",
+			old:       syncedEIP,
+			update:    func(eip *EIP) { eip.Spec.SecurityProtectionTypes = nil },
+			wantField: "spec.securityProtectionTypes",
+		},
+	}
+
+	covered := map[string]bool{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := tt.old()
+			eip := old.DeepCopy()
+			tt.update(eip)
+
+			errs := eip.validateSpecUpdate(old)
+			switch {
+			case tt.wantField == "" && len(errs) > 0:
+				t.Errorf("unexpected errors: %v", errs)
+			case tt.wantField != "" && len(errs) != 1:
+				t.Errorf("got %d errors %v, want one on %s", len(errs), errs, tt.wantField)
+			case tt.wantField != "" && errs[0].Field != tt.wantField:
+				t.Errorf("error on %s, want %s", errs[0].Field, tt.wantField)
+			}
+		})
+		covered[tt.wantField] = true
+	}
+
+	// Every field that cannot be changed in place needs a row above
+	for _, f := range EIPSpecFields {
+		if f.Mutability != FieldMutableInPlace && !covered["spec."+f.Name] {
+			t.Errorf("EIPSpecFields row %s (%s) is not covered", f.Name, f.Mutability)
+		}
+	}
+}
+
+func TestDefault(t *testing.T) {
+	withDefaultRegionID(t, "cn-hangzhou")
+
+	defaulted := EIPSpec{
+		RegionID:           "cn-hangzhou",
+		InternetChargeType: DefaultInternetChargeType,
+		InstanceChargeType: DefaultInstanceChargeType,
+		Description:        DefaultEIPDescription,
+		Name:               "k8s-default-web",
+		ReleaseStrategy:    ReleaseStrategyOnDeleteIfCreated,
+		DeletionPolicy:     DeletionPolicyBlock,
+	}
+
+	tests := []struct {
+		name string
+		eip  *EIP
+		want EIPSpec
+	}{
+		{
+			name: "new EIP",
+			eip:  &EIP{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+			want: defaulted,
+		},
+		{
+			name: "set fields are kept",
+			eip: &EIP{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec: EIPSpec{
+					RegionID:           "cn-beijing",
+					InternetChargeType: "PayByBandwidth",
+					InstanceChargeType: "PrePaid",
+					Description:        "web eip",
+					Name:               "web",
+					ReleaseStrategy:    ReleaseStrategyNever,
+					DeletionPolicy:     DeletionPolicyOrphan,
+				},
+			},
+			want: EIPSpec{
+				RegionID:           "cn-beijing",
+				InternetChargeType: "PayByBandwidth",
+				InstanceChargeType: "PrePaid",
+				Description:        "web eip",
+				Name:               "web",
+				ReleaseStrategy:    ReleaseStrategyNever,
+				DeletionPolicy:     DeletionPolicyOrphan,
+			},
+		},
+		{
+			name: "generateName leaves the cloud name to the controller",
+			eip:  &EIP{ObjectMeta: metav1.ObjectMeta{GenerateName: "web-", Namespace: "default"}},
+			want: func() EIPSpec {
+				spec := defaulted
+				spec.Name = ""
+				return spec
+			}(),
+		},
+		{
+			name: "synced EIP keeps its region",
+			eip: &EIP{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Status:     EIPStatus{AllocationID: "eip-123", RegionID: "cn-beijing"},
+			},
+			want: EIPSpec{
+				RegionID:        "cn-beijing",
+				ReleaseStrategy: ReleaseStrategyOnDeleteIfCreated,
+				DeletionPolicy:  DeletionPolicyBlock,
+			},
+		},
+		{
+			name: "adopted EIP is not defaulted",
+			eip: &EIP{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec:       EIPSpec{AllocationID: "eip-123"},
+			},
+			want: EIPSpec{
+				AllocationID:    "eip-123",
+				RegionID:        "cn-hangzhou",
+				ReleaseStrategy: ReleaseStrategyOnDeleteIfCreated,
+				DeletionPolicy:  DeletionPolicyBlock,
+			},
+		},
+		{
+			name: "imported EIP is not defaulted",
+			eip: &EIP{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec:       EIPSpec{ImportFrom: &EIPImportSource{IPAddress: "47.0.0.1"}},
+			},
+			want: EIPSpec{
+				ImportFrom:      &EIPImportSource{IPAddress: "47.0.0.1"},
+				RegionID:        "cn-hangzhou",
+				ReleaseStrategy: ReleaseStrategyOnDeleteIfCreated,
+				DeletionPolicy:  DeletionPolicyBlock,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.eip.Default()
+			if !equality.Semantic.DeepEqual(tt.eip.Spec, tt.want) {
+				t.Errorf("Default() spec = %+v, want %+v", tt.eip.Spec, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultCloudName(t *testing.T) {
+	long := strings.Repeat("a", 130)
+	if got := defaultCloudName("default", "web"); got != "k8s-default-web" {
+		t.Errorf("defaultCloudName() = %q, want k8s-default-web", got)
+	}
+	if got := defaultCloudName("default", long); len(got) != 128 {
+		t.Errorf("defaultCloudName() length = %d, want 128", len(got))
+	}
+}
+
+func TestValidateDelete(t *testing.T) {
+	inUse := func(mutate func(eip *EIP)) *EIP {
+		eip := &EIP{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: EIPSpec{
+				ReleaseStrategy: ReleaseStrategyOnDeleteIfCreated,
+				DeletionPolicy:  DeletionPolicyBlock,
+			},
+			Status: EIPStatus{
+				AllocationID: "eip-123",
+				Provenance:   EIPProvenanceCreated,
+				InstanceID:   "i-123",
+				InstanceType: "EcsInstance",
+			},
+		}
+		mutate(eip)
+		return eip
+	}
+
+	tests := []struct {
+		name        string
+		eip         *EIP
+		wantErr     bool
+		wantWarning bool
+	}{
+		{
+			name:    "in use and released is blocked",
+			eip:     inUse(func(eip *EIP) {}),
+			wantErr: true,
+		},
+		{
+			name:    "unset deletionPolicy blocks",
+			eip:     inUse(func(eip *EIP) { eip.Spec.DeletionPolicy = "" }),
+			wantErr: true,
+		},
+		{
+			name: "not associated",
+			eip:  inUse(func(eip *EIP) { eip.Status.InstanceID = "" }),
+		},
+		{
+			name: "imported EIP is kept",
+			eip:  inUse(func(eip *EIP) { eip.Status.Provenance = EIPProvenanceImported }),
+		},
+		{
+			name: "never released",
+			eip:  inUse(func(eip *EIP) { eip.Spec.ReleaseStrategy = ReleaseStrategyNever }),
+		},
+		{
+			name: "not created yet",
+			eip:  inUse(func(eip *EIP) { eip.Status.AllocationID = "" }),
+		},
+		{
+			name: "disassociate then release",
+			eip:  inUse(func(eip *EIP) { eip.Spec.DeletionPolicy = DeletionPolicyDisassociateThenRelease }),
+		},
+		{
+			name: "orphan",
+			eip:  inUse(func(eip *EIP) { eip.Spec.DeletionPolicy = DeletionPolicyOrphan }),
+		},
+		{
+			name: "override annotation",
+			eip: inUse(func(eip *EIP) {
+				eip.Annotations = map[string]string{AnnotationDeletionPolicyOverride: string(DeletionPolicyOrphan)}
+			}),
+			wantWarning: true,
+		},
+		{
+			name: "invalid override annotation",
+			eip: inUse(func(eip *EIP) {
+				eip.Annotations = map[string]string{AnnotationDeletionPolicyOverride: string(DeletionPolicyBlock)}
+			}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := tt.eip.ValidateDelete()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDelete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !apierrors.IsForbidden(err) {
+				t.Errorf("ValidateDelete() error = %v, want Forbidden", err)
+			}
+			if (len(warnings) > 0) != tt.wantWarning {
+				t.Errorf("ValidateDelete() warnings = %v, wantWarning %v", warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestValidateImportFrom(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      EIPSpec
+		wantField string
+	}{
+		{
+			name: "not importing",
+		},
+		{
+			name: "by ip address",
+			spec: EIPSpec{ImportFrom: &EIPImportSource{IPAddress: "47.0.0.1"}},
+		},
+		{
+			name: "by tag selector",
+			spec: EIPSpec{ImportFrom: &EIPImportSource{TagSelector: map[string]string{"env": "prod"}}},
+		},
+		{
+			name: "with allocationID",
+			spec: EIPSpec{
+				AllocationID: "eip-123",
+				ImportFrom:   &EIPImportSource{IPAddress: "47.0.0.1"},
+			},
+			wantField: "spec.importFrom",
+		},
+		{
+			name:      "no source",
+			spec:      EIPSpec{ImportFrom: &EIPImportSource{}},
+			wantField: "spec.importFrom",
+		},
+		{
+			name: "both sources",
+			spec: EIPSpec{ImportFrom: &EIPImportSource{
+				IPAddress:   "47.0.0.1",
+				TagSelector: map[string]string{"env": "prod"},
+			}},
+			wantField: "spec.importFrom",
+		},
+		{
+			name:      "invalid ip address",
+			spec:      EIPSpec{ImportFrom: &EIPImportSource{IPAddress: "47.0.0"}},
+			wantField: "spec.importFrom.ipAddress",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eip := &EIP{Spec: tt.spec}
+			err := eip.validateImportFrom()
+			switch {
+			case tt.wantField == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantField != "" && err == nil:
+				t.Errorf("expected an error on %s", tt.wantField)
+			case tt.wantField != "" && err.Field != tt.wantField:
+				t.Errorf("error on %s, want %s", err.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateAllocationIDUnique(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	existing := &EIP{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a"},
+		Spec:       EIPSpec{AllocationID: "eip-123"},
+		Status:     EIPStatus{AllocationID: "eip-123"},
+	}
+	created := &EIP{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a"},
+		Status:     EIPStatus{AllocationID: "eip-456"},
+	}
+	reader := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(existing, created).
+		WithIndex(&EIP{}, EIPAllocationIDField, func(obj client.Object) []string {
+			return obj.(*EIP).AllocationIDs()
+		}).
+		Build()
+
+	previous := eipWebhookReader
+	eipWebhookReader = reader
+	t.Cleanup(func() { eipWebhookReader = previous })
+
+	tests := []struct {
+		name    string
+		eip     *EIP
+		old     *EIP
+		wantErr bool
+	}{
+		{
+			name: "unused allocationID",
+			eip:  &EIP{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"}, Spec: EIPSpec{AllocationID: "eip-789"}},
+		},
+		{
+			name: "no allocationID",
+			eip:  &EIP{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"}},
+		},
+		{
+			name:    "referenced in the same namespace",
+			eip:     &EIP{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"}, Spec: EIPSpec{AllocationID: "eip-123"}},
+			wantErr: true,
+		},
+		{
+			name:    "referenced in another namespace",
+			eip:     &EIP{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-b"}, Spec: EIPSpec{AllocationID: "eip-123"}},
+			wantErr: true,
+		},
+		{
+			name:    "created by another EIP",
+			eip:     &EIP{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"}, Spec: EIPSpec{AllocationID: "eip-456"}},
+			wantErr: true,
+		},
+		{
+			name: "the EIP itself",
+			eip:  existing.DeepCopy(),
+		},
+		{
+			name: "unchanged on update",
+			eip:  &EIP{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"}, Spec: EIPSpec{AllocationID: "eip-123"}},
+			old:  &EIP{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"}, Spec: EIPSpec{AllocationID: "eip-123"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.eip.validateAllocationIDUnique(tt.old)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateAllocationIDUnique() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
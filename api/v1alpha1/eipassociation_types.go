@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AssociationInstanceType 定义EIP可绑定的实例类型
+// +kubebuilder:validation:Enum=EcsInstance;NetworkInterface;SlbInstance;Nat;HaVip
+type AssociationInstanceType string
+
+const (
+	// AssociationInstanceTypeEcsInstance 绑定到ECS实例
+	AssociationInstanceTypeEcsInstance AssociationInstanceType = "EcsInstance"
+	// AssociationInstanceTypeNetworkInterface 绑定到弹性网卡
+	AssociationInstanceTypeNetworkInterface AssociationInstanceType = "NetworkInterface"
+	// AssociationInstanceTypeSlbInstance 绑定到负载均衡实例
+	AssociationInstanceTypeSlbInstance AssociationInstanceType = "SlbInstance"
+	// AssociationInstanceTypeNat 绑定到NAT网关
+	AssociationInstanceTypeNat AssociationInstanceType = "Nat"
+	// AssociationInstanceTypeHaVip 绑定到高可用虚拟IP
+	AssociationInstanceTypeHaVip AssociationInstanceType = "HaVip"
+)
+
+// EIPAssociationSpec defines the desired state of EIPAssociation
+type EIPAssociationSpec struct {
+	// EIPName 引用的EIP资源名称，必须与EIPAssociation位于同一命名空间
+	// +kubebuilder:validation:MinLength=1
+	EIPName string `json:"eipName"`
+
+	// InstanceID 绑定目标实例ID
+	// +kubebuilder:validation:MinLength=1
+	InstanceID string `json:"instanceID"`
+
+	// InstanceType 绑定目标实例类型
+	// +kubebuilder:default:=EcsInstance
+	// +optional
+	InstanceType AssociationInstanceType `json:"instanceType,omitempty"`
+
+	// PrivateIPAddress 绑定到弹性网卡时指定的私网IP，不指定则绑定到主私网IP
+	// +optional
+	PrivateIPAddress string `json:"privateIPAddress,omitempty"`
+}
+
+// EIPAssociationStatus defines the observed state of EIPAssociation
+type EIPAssociationStatus struct {
+	// AllocationID 已绑定的EIP实例ID
+	AllocationID string `json:"allocationID,omitempty"`
+
+	// EIPAddress EIP地址
+	EIPAddress string `json:"eipAddress,omitempty"`
+
+	// RegionID 已绑定的EIP所在地域，解绑时使用
+	RegionID string `json:"regionID,omitempty"`
+
+	// CloudAccountName 绑定的EIP所属的CloudAccount，解绑时使用
+	CloudAccountName string `json:"cloudAccountName,omitempty"`
+
+	// InstanceID 当前绑定的实例ID
+	InstanceID string `json:"instanceID,omitempty"`
+
+	// InstanceType 当前绑定的实例类型
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// PrivateIPAddress 当前绑定的私网IP
+	PrivateIPAddress string `json:"privateIPAddress,omitempty"`
+
+	// Status EIP状态
+	Status string `json:"status,omitempty"`
+
+	// Conditions 绑定状态条件
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastSyncTime 最后同步时间
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=eipa
+//+kubebuilder:printcolumn:name="EIP",type=string,JSONPath=`.spec.eipName`
+//+kubebuilder:printcolumn:name="EIP Address",type=string,JSONPath=`.status.eipAddress`
+//+kubebuilder:printcolumn:name="InstanceID",type=string,JSONPath=`.status.instanceID`
+//+kubebuilder:printcolumn:name="InstanceType",type=string,JSONPath=`.status.instanceType`
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// EIPAssociation is the Schema for the eipassociations API
+type EIPAssociation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EIPAssociationSpec   `json:"spec,omitempty"`
+	Status EIPAssociationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// EIPAssociationList contains a list of EIPAssociation
+type EIPAssociationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EIPAssociation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EIPAssociation{}, &EIPAssociationList{})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EIPReclaimPolicy 定义EIPClaim删除后已绑定EIP的处理方式。它只作用于集群内的EIP资源，
+// 云上EIP是否释放仍由EIP的ReleaseStrategy决定：只有Delete会删除EIP资源并触发ReleaseStrategy，
+// Retain和Recycle都保留EIP资源，云上EIP不受影响
+// +kubebuilder:validation:Enum=Retain;Recycle;Delete
+type EIPReclaimPolicy string
+
+const (
+	// EIPReclaimPolicyRetain 保留EIP并保持占用标记，需要管理员手动回收
+	EIPReclaimPolicyRetain EIPReclaimPolicy = "Retain"
+	// EIPReclaimPolicyRecycle 清除占用标记，EIP可被其他EIPClaim再次绑定
+	EIPReclaimPolicyRecycle EIPReclaimPolicy = "Recycle"
+	// EIPReclaimPolicyDelete 删除EIP资源，云上EIP是否释放由EIP的ReleaseStrategy决定
+	EIPReclaimPolicyDelete EIPReclaimPolicy = "Delete"
+)
+
+// EIPClaimPhase EIPClaim所处阶段
+type EIPClaimPhase string
+
+const (
+	// EIPClaimPending 等待绑定EIP
+	EIPClaimPending EIPClaimPhase = "Pending"
+	// EIPClaimBound 已绑定EIP
+	EIPClaimBound EIPClaimPhase = "Bound"
+	// EIPClaimLost 已绑定的EIP不存在
+	EIPClaimLost EIPClaimPhase = "Lost"
+)
+
+// EIPClaimSpec defines the desired state of EIPClaim
+// className、poolName、eipName 三者至少指定一个
+type EIPClaimSpec struct {
+	// ClassName 申请的EIPClass名称，优先绑定该类别的空闲EIP，没有时按类别模板创建
+	// +optional
+	ClassName string `json:"className,omitempty"`
+
+	// PoolName 从同命名空间的EIPPool中申请空闲EIP
+	// +optional
+	PoolName string `json:"poolName,omitempty"`
+
+	// EIPName 直接绑定同命名空间下指定的EIP
+	// +optional
+	EIPName string `json:"eipName,omitempty"`
+
+	// ReclaimPolicy 删除EIPClaim后EIP的处理方式，按EIPClass申请时默认使用EIPClass的配置，
+	// 从EIPPool申请或指定eipName时默认为Recycle
+	// +optional
+	ReclaimPolicy EIPReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+// EIPClaimStatus defines the observed state of EIPClaim
+type EIPClaimStatus struct {
+	// Phase EIPClaim所处阶段
+	Phase EIPClaimPhase `json:"phase,omitempty"`
+
+	// EIPName 已绑定的EIP资源名称
+	EIPName string `json:"eipName,omitempty"`
+
+	// AllocationID 已绑定的EIP实例ID
+	AllocationID string `json:"allocationID,omitempty"`
+
+	// EIPAddress 已绑定的EIP地址
+	EIPAddress string `json:"eipAddress,omitempty"`
+
+	// ReclaimPolicy 生效的回收策略
+	ReclaimPolicy EIPReclaimPolicy `json:"reclaimPolicy,omitempty"`
+
+	// Conditions 声明状态条件
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=eipc
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="EIP",type=string,JSONPath=`.status.eipName`
+//+kubebuilder:printcolumn:name="EIP Address",type=string,JSONPath=`.status.eipAddress`
+//+kubebuilder:printcolumn:name="Class",type=string,JSONPath=`.spec.className`
+//+kubebuilder:printcolumn:name="Pool",type=string,JSONPath=`.spec.poolName`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// EIPClaim is the Schema for the eipclaims API
+type EIPClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EIPClaimSpec   `json:"spec,omitempty"`
+	Status EIPClaimStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// EIPClaimList contains a list of EIPClaim
+type EIPClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EIPClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EIPClaim{}, &EIPClaimList{})
+}
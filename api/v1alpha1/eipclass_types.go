@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LabelEIPClass 标识EIP所属的EIPClass，带有该标签的空闲EIP可被对应类别的EIPClaim绑定
+	LabelEIPClass = "eip.alibabacloud.com/class"
+)
+
+// EIPClassSpec defines the desired state of EIPClass
+type EIPClassSpec struct {
+	// Template 没有空闲EIP时，按此模板为EIPClaim创建EIP
+	Template EIPTemplateSpec `json:"template"`
+
+	// ReclaimPolicy EIPClaim未指定回收策略时使用的默认值
+	// +kubebuilder:default:=Delete
+	// +optional
+	ReclaimPolicy EIPReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=eipclass
+//+kubebuilder:printcolumn:name="ReclaimPolicy",type=string,JSONPath=`.spec.reclaimPolicy`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// EIPClass is the Schema for the eipclasses API
+type EIPClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec EIPClassSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// EIPClassList contains a list of EIPClass
+type EIPClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EIPClass `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EIPClass{}, &EIPClassList{})
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LabelEIPPool 标识EIP所属的EIPPool
+	LabelEIPPool = "eip.alibabacloud.com/pool"
+	// AnnotationClaimedBy 标识池中EIP的使用方，未设置表示EIP空闲可分配
+	AnnotationClaimedBy = "eip.alibabacloud.com/claimed-by"
+)
+
+// EIPTemplateMeta 池成员EIP的元数据
+type EIPTemplateMeta struct {
+	// Labels 池成员EIP的标签
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations 池成员EIP的注解
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// EIPTemplateSpec 描述池中每个EIP的期望配置
+type EIPTemplateSpec struct {
+	// Metadata 池成员EIP的元数据
+	// +optional
+	Metadata EIPTemplateMeta `json:"metadata,omitempty"`
+
+	// Spec 池成员EIP的配置，allocationID会被忽略
+	Spec EIPSpec `json:"spec"`
+}
+
+// EIPPoolSpec defines the desired state of EIPPool
+type EIPPoolSpec struct {
+	// Template 池中EIP的模板
+	Template EIPTemplateSpec `json:"template"`
+
+	// MinAvailable 池中保持空闲可分配的EIP数量
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default:=1
+	// +optional
+	MinAvailable int32 `json:"minAvailable,omitempty"`
+
+	// MaxSize 池中EIP总数上限，包括已分配的EIP
+	// +kubebuilder:validation:Minimum=1
+	MaxSize int32 `json:"maxSize"`
+}
+
+// EIPPoolStatus defines the observed state of EIPPool
+type EIPPoolStatus struct {
+	// Available 空闲可分配的EIP数量
+	Available int32 `json:"available"`
+
+	// Claimed 已分配出去的EIP数量
+	Claimed int32 `json:"claimed"`
+
+	// Pending 正在创建中的EIP数量
+	Pending int32 `json:"pending"`
+
+	// Total 池中EIP总数
+	Total int32 `json:"total"`
+
+	// Conditions 池状态条件
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastSyncTime 最后同步时间
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=eippool
+//+kubebuilder:printcolumn:name="MinAvailable",type=integer,JSONPath=`.spec.minAvailable`
+//+kubebuilder:printcolumn:name="MaxSize",type=integer,JSONPath=`.spec.maxSize`
+//+kubebuilder:printcolumn:name="Available",type=integer,JSONPath=`.status.available`
+//+kubebuilder:printcolumn:name="Claimed",type=integer,JSONPath=`.status.claimed`
+//+kubebuilder:printcolumn:name="Pending",type=integer,JSONPath=`.status.pending`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// EIPPool is the Schema for the eippools API
+type EIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EIPPoolSpec   `json:"spec,omitempty"`
+	Status EIPPoolStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// EIPPoolList contains a list of EIPPool
+type EIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EIPPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EIPPool{}, &EIPPoolList{})
+}
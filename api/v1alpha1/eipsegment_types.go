@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LabelEIPSegment 标识由EIPSegment创建的成员EIP
+	LabelEIPSegment = "eip.alibabacloud.com/segment"
+)
+
+// EIPSegmentSpec defines the desired state of EIPSegment
+type EIPSegmentSpec struct {
+	// SegmentInstanceID 指定已存在的连续EIP地址段ID，如果指定则不会申请新的地址段
+	// +optional
+	SegmentInstanceID string `json:"segmentInstanceID,omitempty"`
+
+	// RegionID 地址段所在地域，默认为控制器配置的地域，创建后不可修改。成员EIP与地址段位于同一地域
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="regionID is immutable"
+	// +optional
+	RegionID string `json:"regionID,omitempty"`
+
+	// CloudAccountName 管理该地址段使用的CloudAccount，默认取命名空间注解eip.alibabacloud.com/cloud-account，
+	// 都未设置时使用控制器自身的凭证，创建后不可修改。成员EIP使用同一账号
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="cloudAccountName is immutable"
+	// +optional
+	CloudAccountName string `json:"cloudAccountName,omitempty"`
+
+	// EIPMask 地址段掩码，支持27和28
+	// +kubebuilder:validation:Enum=27;28
+	// +kubebuilder:default:=28
+	// +optional
+	EIPMask int32 `json:"eipMask,omitempty"`
+
+	// Bandwidth 每个EIP的带宽，单位Mbps
+	// +optional
+	Bandwidth string `json:"bandwidth,omitempty"`
+
+	// InternetChargeType 计费方式，支持PayByBandwidth和PayByTraffic
+	// +kubebuilder:default:=PayByTraffic
+	// +optional
+	InternetChargeType string `json:"internetChargeType,omitempty"`
+
+	// ISP 线路类型
+	// +optional
+	ISP string `json:"isp,omitempty"`
+
+	// ResourceGroupID 资源组ID
+	// +optional
+	ResourceGroupID string `json:"resourceGroupID,omitempty"`
+
+	// EIPTemplate 设置后为地址段中的每个IP创建一个EIP资源，allocationID和releaseStrategy会被忽略，
+	// 成员EIP随地址段整体释放
+	// +optional
+	EIPTemplate *EIPTemplateSpec `json:"eipTemplate,omitempty"`
+
+	// ReleaseStrategy 地址段释放策略，默认OnDeleteIfCreated，通过segmentInstanceID导入的地址段不会被释放
+	// +kubebuilder:validation:Enum=Never;OnDelete;OnDeleteIfCreated
+	// +kubebuilder:default:=OnDeleteIfCreated
+	// +optional
+	ReleaseStrategy ReleaseStrategy `json:"releaseStrategy,omitempty"`
+}
+
+// EIPSegmentMember 地址段中的EIP
+type EIPSegmentMember struct {
+	// AllocationID EIP实例ID
+	AllocationID string `json:"allocationID"`
+
+	// EIPAddress EIP地址
+	EIPAddress string `json:"eipAddress"`
+
+	// Status EIP状态
+	Status string `json:"status,omitempty"`
+
+	// EIPName 对应的成员EIP资源名称，未设置eipTemplate时为空
+	// +optional
+	EIPName string `json:"eipName,omitempty"`
+}
+
+// EIPSegmentStatus defines the observed state of EIPSegment
+type EIPSegmentStatus struct {
+	// SegmentInstanceID 连续EIP地址段ID
+	SegmentInstanceID string `json:"segmentInstanceID,omitempty"`
+
+	// RegionID 地址段所在地域
+	RegionID string `json:"regionID,omitempty"`
+
+	// CloudAccountName 地址段所属的CloudAccount，为空表示使用控制器自身的凭证
+	CloudAccountName string `json:"cloudAccountName,omitempty"`
+
+	// Provenance 地址段来源，Created或Imported，确定后不再改变
+	// +optional
+	Provenance EIPProvenance `json:"provenance,omitempty"`
+
+	// Segment 地址段CIDR
+	Segment string `json:"segment,omitempty"`
+
+	// Status 地址段状态
+	Status string `json:"status,omitempty"`
+
+	// Members 地址段中的EIP
+	Members []EIPSegmentMember `json:"members,omitempty"`
+
+	// Conditions 地址段状态条件
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastSyncTime 最后同步时间
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=eipseg
+//+kubebuilder:printcolumn:name="SegmentID",type=string,JSONPath=`.status.segmentInstanceID`
+//+kubebuilder:printcolumn:name="Segment",type=string,JSONPath=`.status.segment`
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
+//+kubebuilder:printcolumn:name="Provenance",type=string,JSONPath=`.status.provenance`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// EIPSegment is the Schema for the eipsegments API
+type EIPSegment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EIPSegmentSpec   `json:"spec,omitempty"`
+	Status EIPSegmentStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// EIPSegmentList contains a list of EIPSegment
+type EIPSegmentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EIPSegment `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EIPSegment{}, &EIPSegmentList{})
+}
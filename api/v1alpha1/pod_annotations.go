@@ -0,0 +1,29 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Pod上用于申请EIP的注解，仅对使用独占弹性网卡的Terway Pod生效
+const (
+	// AnnotationPodEIP 指定Pod使用同命名空间下的EIP资源名称
+	AnnotationPodEIP = "eip.alibabacloud.com/eip"
+	// AnnotationPodEIPPool 指定Pod从同命名空间下的EIPPool中申请EIP
+	AnnotationPodEIPPool = "eip.alibabacloud.com/pool"
+	// AnnotationPodEIPAddress 控制器回写的Pod公网IP地址
+	AnnotationPodEIPAddress = "eip.alibabacloud.com/eip-address"
+	// AnnotationPodEIPAllocationID 控制器回写的Pod所使用的EIP实例ID
+	AnnotationPodEIPAllocationID = "eip.alibabacloud.com/eip-allocation-id"
+)
@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PublicIPAddressPoolCidrBlock 地址池中的IP地址段，cidrBlock和cidrMask二选一
+type PublicIPAddressPoolCidrBlock struct {
+	// CidrBlock 指定要添加的IP地址段
+	// +optional
+	CidrBlock string `json:"cidrBlock,omitempty"`
+
+	// CidrMask 由阿里云自动分配的IP地址段掩码
+	// +kubebuilder:validation:Minimum=24
+	// +kubebuilder:validation:Maximum=28
+	// +optional
+	CidrMask int32 `json:"cidrMask,omitempty"`
+}
+
+// PublicIPAddressPoolSpec defines the desired state of PublicIPAddressPool
+type PublicIPAddressPoolSpec struct {
+	// PublicIPAddressPoolID 指定已存在的公网IP地址池ID，如果指定则不会创建新的地址池
+	// +optional
+	PublicIPAddressPoolID string `json:"publicIPAddressPoolID,omitempty"`
+
+	// RegionID 地址池所在地域，默认为控制器配置的地域，创建后不可修改
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="regionID is immutable"
+	// +optional
+	RegionID string `json:"regionID,omitempty"`
+
+	// CloudAccountName 管理该地址池使用的CloudAccount，未设置时使用控制器自身的凭证，创建后不可修改
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="cloudAccountName is immutable"
+	// +optional
+	CloudAccountName string `json:"cloudAccountName,omitempty"`
+
+	// ISP 线路类型
+	// +kubebuilder:default:=BGP
+	// +optional
+	ISP string `json:"isp,omitempty"`
+
+	// ResourceGroupID 资源组ID
+	// +optional
+	ResourceGroupID string `json:"resourceGroupID,omitempty"`
+
+	// Name 地址池名称
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Description 地址池描述
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// CidrBlocks 地址池中的IP地址段，从列表中移除且没有已使用IP的地址段会被删除
+	// +optional
+	CidrBlocks []PublicIPAddressPoolCidrBlock `json:"cidrBlocks,omitempty"`
+
+	// ReleaseStrategy 地址池释放策略，默认OnDeleteIfCreated，通过publicIPAddressPoolID导入的地址池不会被释放
+	// +kubebuilder:validation:Enum=Never;OnDelete;OnDeleteIfCreated
+	// +kubebuilder:default:=OnDeleteIfCreated
+	// +optional
+	ReleaseStrategy ReleaseStrategy `json:"releaseStrategy,omitempty"`
+}
+
+// PublicIPAddressPoolCidrBlockStatus 地址池中IP地址段的状态
+type PublicIPAddressPoolCidrBlockStatus struct {
+	// CidrBlock IP地址段
+	CidrBlock string `json:"cidrBlock"`
+
+	// Status IP地址段状态
+	Status string `json:"status,omitempty"`
+
+	// Total IP地址段中的IP总数
+	Total int32 `json:"total"`
+
+	// Used IP地址段中已使用的IP数量
+	Used int32 `json:"used"`
+}
+
+// PublicIPAddressPoolStatus defines the observed state of PublicIPAddressPool
+type PublicIPAddressPoolStatus struct {
+	// PublicIPAddressPoolID 公网IP地址池ID
+	PublicIPAddressPoolID string `json:"publicIPAddressPoolID,omitempty"`
+
+	// RegionID 地址池所在地域
+	RegionID string `json:"regionID,omitempty"`
+
+	// CloudAccountName 地址池所属的CloudAccount，为空表示使用控制器自身的凭证
+	CloudAccountName string `json:"cloudAccountName,omitempty"`
+
+	// Provenance 地址池来源，Created或Imported，确定后不再改变
+	// +optional
+	Provenance EIPProvenance `json:"provenance,omitempty"`
+
+	// CidrBlocks 地址池中的IP地址段
+	CidrBlocks []PublicIPAddressPoolCidrBlockStatus `json:"cidrBlocks,omitempty"`
+
+	// Total 地址池中的IP总数
+	Total int32 `json:"total"`
+
+	// Used 地址池中已使用的IP数量
+	Used int32 `json:"used"`
+
+	// Free 地址池中剩余可分配的IP数量
+	Free int32 `json:"free"`
+
+	// Conditions 地址池状态条件
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastSyncTime 最后同步时间
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster,shortName=pipp
+//+kubebuilder:printcolumn:name="PoolID",type=string,JSONPath=`.status.publicIPAddressPoolID`
+//+kubebuilder:printcolumn:name="Total",type=integer,JSONPath=`.status.total`
+//+kubebuilder:printcolumn:name="Used",type=integer,JSONPath=`.status.used`
+//+kubebuilder:printcolumn:name="Free",type=integer,JSONPath=`.status.free`
+//+kubebuilder:printcolumn:name="Provenance",type=string,JSONPath=`.status.provenance`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PublicIPAddressPool is the Schema for the publicipaddresspools API
+type PublicIPAddressPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PublicIPAddressPoolSpec   `json:"spec,omitempty"`
+	Status PublicIPAddressPoolStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PublicIPAddressPoolList contains a list of PublicIPAddressPool
+type PublicIPAddressPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PublicIPAddressPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PublicIPAddressPool{}, &PublicIPAddressPoolList{})
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+const (
+	// AnnotationV1alpha1Bandwidth 保存无法转换为整数的v1alpha1 spec.bandwidth，转换回v1alpha1时恢复
+	AnnotationV1alpha1Bandwidth = "eip.alibabacloud.com/v1alpha1-bandwidth"
+	// AnnotationV1alpha1ObservedBandwidth 保存无法转换为整数的v1alpha1 status.bandwidth
+	AnnotationV1alpha1ObservedBandwidth = "eip.alibabacloud.com/v1alpha1-observed-bandwidth"
+)
+
+// SetupWebhookWithManager registers the conversion webhook for EIP
+func (r *EIP) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+var _ conversion.Convertible = &EIP{}
+
+// ConvertTo converts this EIP to the hub version (v1alpha1)
+func (r *EIP) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.EIP)
+	if !ok {
+		return fmt.Errorf("unexpected hub type %T", dstRaw)
+	}
+
+	dst.ObjectMeta = *r.ObjectMeta.DeepCopy()
+	annotations := dst.GetAnnotations()
+
+	dst.Spec = v1alpha1.EIPSpec{
+		AllocationID:            r.Spec.AllocationID,
+		RegionID:                r.Spec.RegionID,
+		CloudAccountName:        r.Spec.CloudAccountName,
+		ImportFrom:              (*v1alpha1.EIPImportSource)(r.Spec.ImportFrom),
+		Bandwidth:               formatBandwidth(r.Spec.Billing.Bandwidth, annotations[AnnotationV1alpha1Bandwidth]),
+		InternetChargeType:      string(r.Spec.Billing.InternetChargeType),
+		InstanceChargeType:      string(r.Spec.Billing.InstanceChargeType),
+		ISP:                     string(r.Spec.Networking.ISP),
+		PublicIPAddressPoolID:   r.Spec.Networking.PublicIPAddressPoolID,
+		ResourceGroupID:         r.Spec.ResourceGroupID,
+		Name:                    r.Spec.Name,
+		Description:             r.Spec.Description,
+		SecurityProtectionTypes: r.Spec.Protection.SecurityProtectionTypes,
+		DeletionProtection:      r.Spec.Protection.DeletionProtection,
+		Tags:                    r.Spec.Tags,
+		BandwidthPackageID:      r.Spec.Networking.BandwidthPackageID,
+		BandwidthPackageName:    r.Spec.Networking.BandwidthPackageName,
+		ReleaseStrategy:         v1alpha1.ReleaseStrategy(r.Spec.ReleaseStrategy),
+		DeletionPolicy:          v1alpha1.DeletionPolicy(r.Spec.DeletionPolicy),
+	}
+
+	dst.Status = v1alpha1.EIPStatus{
+		AllocationID:          r.Status.AllocationID,
+		RegionID:              r.Status.RegionID,
+		CloudAccountName:      r.Status.CloudAccountName,
+		EIPAddress:            r.Status.EIPAddress,
+		Status:                string(r.Status.State),
+		Provenance:            v1alpha1.EIPProvenance(r.Status.Provenance),
+		ISP:                   string(r.Status.Networking.ISP),
+		InternetChargeType:    string(r.Status.Billing.InternetChargeType),
+		InstanceChargeType:    string(r.Status.Billing.InstanceChargeType),
+		Bandwidth:             formatBandwidth(r.Status.Billing.Bandwidth, annotations[AnnotationV1alpha1ObservedBandwidth]),
+		BandwidthPackageID:    r.Status.Networking.BandwidthPackageID,
+		ResourceGroupID:       r.Status.ResourceGroupID,
+		Name:                  r.Status.Name,
+		PublicIPAddressPoolID: r.Status.Networking.PublicIPAddressPoolID,
+		Description:           r.Status.Description,
+		InstanceID:            r.Status.Association.InstanceID,
+		InstanceType:          r.Status.Association.InstanceType,
+		PrivateIPAddress:      r.Status.Association.PrivateIPAddress,
+		DeletionProtection:    r.Status.Protection.DeletionProtection,
+		Tags:                  r.Status.Tags,
+		Conditions:            r.Status.Conditions,
+		LastSyncTime:          r.Status.LastSyncTime,
+	}
+
+	// 保存的原始值已恢复，不再保留在hub版本中
+	delete(annotations, AnnotationV1alpha1Bandwidth)
+	delete(annotations, AnnotationV1alpha1ObservedBandwidth)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	dst.SetAnnotations(annotations)
+
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1alpha1) to this version
+func (r *EIP) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.EIP)
+	if !ok {
+		return fmt.Errorf("unexpected hub type %T", srcRaw)
+	}
+
+	r.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	bandwidth, bandwidthOK := parseBandwidth(src.Spec.Bandwidth)
+	observedBandwidth, observedBandwidthOK := parseBandwidth(src.Status.Bandwidth)
+	if !bandwidthOK || !observedBandwidthOK {
+		annotations := r.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		if !bandwidthOK {
+			annotations[AnnotationV1alpha1Bandwidth] = src.Spec.Bandwidth
+		}
+		if !observedBandwidthOK {
+			annotations[AnnotationV1alpha1ObservedBandwidth] = src.Status.Bandwidth
+		}
+		r.SetAnnotations(annotations)
+	}
+
+	r.Spec = EIPSpec{
+		AllocationID:     src.Spec.AllocationID,
+		RegionID:         src.Spec.RegionID,
+		CloudAccountName: src.Spec.CloudAccountName,
+		ImportFrom:       (*EIPImportSource)(src.Spec.ImportFrom),
+		Name:             src.Spec.Name,
+		Description:      src.Spec.Description,
+		ResourceGroupID:  src.Spec.ResourceGroupID,
+		Billing: EIPBilling{
+			InternetChargeType: InternetChargeType(src.Spec.InternetChargeType),
+			InstanceChargeType: InstanceChargeType(src.Spec.InstanceChargeType),
+			Bandwidth:          bandwidth,
+		},
+		Networking: EIPNetworking{
+			ISP:                   ISP(src.Spec.ISP),
+			PublicIPAddressPoolID: src.Spec.PublicIPAddressPoolID,
+			BandwidthPackageID:    src.Spec.BandwidthPackageID,
+			BandwidthPackageName:  src.Spec.BandwidthPackageName,
+		},
+		Protection: EIPProtection{
+			SecurityProtectionTypes: src.Spec.SecurityProtectionTypes,
+			DeletionProtection:      src.Spec.DeletionProtection,
+		},
+		Tags:            src.Spec.Tags,
+		ReleaseStrategy: ReleaseStrategy(src.Spec.ReleaseStrategy),
+		DeletionPolicy:  DeletionPolicy(src.Spec.DeletionPolicy),
+	}
+
+	r.Status = EIPStatus{
+		AllocationID:     src.Status.AllocationID,
+		RegionID:         src.Status.RegionID,
+		CloudAccountName: src.Status.CloudAccountName,
+		EIPAddress:       src.Status.EIPAddress,
+		State:            EIPState(src.Status.Status),
+		Provenance:       EIPProvenance(src.Status.Provenance),
+		Name:             src.Status.Name,
+		Description:      src.Status.Description,
+		ResourceGroupID:  src.Status.ResourceGroupID,
+		Billing: EIPObservedBilling{
+			InternetChargeType: InternetChargeType(src.Status.InternetChargeType),
+			InstanceChargeType: InstanceChargeType(src.Status.InstanceChargeType),
+			Bandwidth:          observedBandwidth,
+		},
+		Networking: EIPObservedNetworking{
+			ISP:                   ISP(src.Status.ISP),
+			PublicIPAddressPoolID: src.Status.PublicIPAddressPoolID,
+			BandwidthPackageID:    src.Status.BandwidthPackageID,
+		},
+		Association: EIPAssociationInfo{
+			InstanceID:       src.Status.InstanceID,
+			InstanceType:     src.Status.InstanceType,
+			PrivateIPAddress: src.Status.PrivateIPAddress,
+		},
+		Protection: EIPObservedProtection{
+			DeletionProtection: src.Status.DeletionProtection,
+		},
+		Tags:         src.Status.Tags,
+		Conditions:   src.Status.Conditions,
+		LastSyncTime: src.Status.LastSyncTime,
+	}
+
+	return nil
+}
+
+// parseBandwidth 将v1alpha1的字符串带宽转换为整数，空值视为未设置
+func parseBandwidth(bandwidth string) (int32, bool) {
+	if bandwidth == "" {
+		return 0, true
+	}
+	// 只接受规范的正整数写法，其余取值保留原文以免往返转换后丢失
+	value, err := strconv.ParseInt(bandwidth, 10, 32)
+	if err != nil || value <= 0 || strconv.FormatInt(value, 10) != bandwidth {
+		return 0, false
+	}
+	return int32(value), true
+}
+
+// formatBandwidth 将整数带宽转换为v1alpha1的字符串，未设置时恢复转换时保存的原始值
+func formatBandwidth(bandwidth int32, original string) string {
+	if bandwidth == 0 {
+		return original
+	}
+	return strconv.FormatInt(int64(bandwidth), 10)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+// hubEIP returns a v1alpha1 EIP with every field set
+func hubEIP(bandwidth, observedBandwidth string, annotations map[string]string) *v1alpha1.EIP {
+	now := metav1.Now()
+	deletionProtection := true
+	return &v1alpha1.EIP{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "eip",
+			Namespace:   "default",
+			Labels:      map[string]string{"app": "web"},
+			Annotations: annotations,
+		},
+		Spec: v1alpha1.EIPSpec{
+			AllocationID:            "eip-123",
+			RegionID:                "cn-beijing",
+			CloudAccountName:        "tenant-a",
+			ImportFrom:              &v1alpha1.EIPImportSource{TagSelector: map[string]string{"env": "prod"}},
+			Bandwidth:               bandwidth,
+			InternetChargeType:      "PayByTraffic",
+			InstanceChargeType:      "PostPaid",
+			ISP:                     "BGP",
+			PublicIPAddressPoolID:   "pippool-123",
+			ResourceGroupID:         "rg-123",
+			Name:                    "web",
+			Description:             "web eip",
+			SecurityProtectionTypes: []string{"AntiDDoS_Enhanced"},
+			DeletionProtection:      &deletionProtection,
+			Tags:                    map[string]string{"team": "net"},
+			BandwidthPackageID:      "cbwp-123",
+			BandwidthPackageName:    "shared",
+			ReleaseStrategy:         v1alpha1.ReleaseStrategyOnDeleteIfCreated,
+			DeletionPolicy:          v1alpha1.DeletionPolicyBlock,
+		},
+		Status: v1alpha1.EIPStatus{
+			AllocationID:          "eip-123",
+			RegionID:              "cn-beijing",
+			CloudAccountName:      "tenant-a",
+			EIPAddress:            "47.0.0.1",
+			Status:                "InUse",
+			Provenance:            v1alpha1.EIPProvenanceImported,
+			ISP:                   "BGP",
+			InternetChargeType:    "PayByTraffic",
+			InstanceChargeType:    "PostPaid",
+			Bandwidth:             observedBandwidth,
+			BandwidthPackageID:    "cbwp-123",
+			ResourceGroupID:       "rg-123",
+			Name:                  "web",
+			PublicIPAddressPoolID: "pippool-123",
+			Description:           "web eip",
+			InstanceID:            "eni-123",
+			InstanceType:          "NetworkInterface",
+			PrivateIPAddress:      "10.0.0.1",
+			DeletionProtection:    true,
+			Tags:                  map[string]string{"team": "net"},
+			Conditions: []metav1.Condition{{
+				Type: "Ready", Status: metav1.ConditionTrue, Reason: "Available", LastTransitionTime: now,
+			}},
+			LastSyncTime: &now,
+		},
+	}
+}
+
+func TestEIPConversionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name              string
+		hub               *v1alpha1.EIP
+		wantBandwidth     int32
+		wantObserved      int32
+		wantAnnotations   map[string]string
+		wantNoAnnotations bool
+	}{
+		{
+			name:              "integer bandwidth",
+			hub:               hubEIP("10", "10", nil),
+			wantBandwidth:     10,
+			wantObserved:      10,
+			wantNoAnnotations: true,
+		},
+		{
+			name:              "unset bandwidth",
+			hub:               hubEIP("", "", nil),
+			wantNoAnnotations: true,
+		},
+		{
+			name:            "fractional spec bandwidth",
+			hub:             hubEIP("1.5", "10", nil),
+			wantObserved:    10,
+			wantAnnotations: map[string]string{AnnotationV1alpha1Bandwidth: "1.5"},
+		},
+		{
+			name:            "non canonical bandwidth",
+			hub:             hubEIP("05", "abc", map[string]string{"owner": "team-a"}),
+			wantAnnotations: map[string]string{"owner": "team-a", AnnotationV1alpha1Bandwidth: "05", AnnotationV1alpha1ObservedBandwidth: "abc"},
+		},
+		{
+			name:            "zero bandwidth",
+			hub:             hubEIP("0", "10", nil),
+			wantObserved:    10,
+			wantAnnotations: map[string]string{AnnotationV1alpha1Bandwidth: "0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.hub.DeepCopy()
+
+			spoke := &EIP{}
+			if err := spoke.ConvertFrom(tt.hub); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			if spoke.Spec.Billing.Bandwidth != tt.wantBandwidth {
+				t.Errorf("spec.billing.bandwidth = %d, want %d", spoke.Spec.Billing.Bandwidth, tt.wantBandwidth)
+			}
+			if spoke.Status.Billing.Bandwidth != tt.wantObserved {
+				t.Errorf("status.billing.bandwidth = %d, want %d", spoke.Status.Billing.Bandwidth, tt.wantObserved)
+			}
+			if tt.wantNoAnnotations && spoke.Annotations != nil {
+				t.Errorf("annotations = %v, want none", spoke.Annotations)
+			}
+			if tt.wantAnnotations != nil && !apiequality.Semantic.DeepEqual(spoke.Annotations, tt.wantAnnotations) {
+				t.Errorf("annotations = %v, want %v", spoke.Annotations, tt.wantAnnotations)
+			}
+			if !apiequality.Semantic.DeepEqual(tt.hub, original) {
+				t.Errorf("ConvertFrom() modified the hub object")
+			}
+
+			hub := &v1alpha1.EIP{}
+			if err := spoke.ConvertTo(hub); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+			if !apiequality.Semantic.DeepEqual(hub, original) {
+				t.Errorf("round trip mismatch\ngot:  %+v\nwant: %+v", hub, original)
+			}
+		})
+	}
+}
+
+func TestEIPConversionFromSpoke(t *testing.T) {
+	spoke := &EIP{}
+	if err := spoke.ConvertFrom(hubEIP("100", "100", map[string]string{"owner": "team-a"})); err != nil {
+		t.Fatal(err)
+	}
+	original := spoke.DeepCopy()
+
+	hub := &v1alpha1.EIP{}
+	if err := spoke.ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if hub.Spec.Bandwidth != "100" || hub.Status.Bandwidth != "100" {
+		t.Errorf("bandwidth = %q/%q, want 100/100", hub.Spec.Bandwidth, hub.Status.Bandwidth)
+	}
+
+	roundTrip := &EIP{}
+	if err := roundTrip.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if !apiequality.Semantic.DeepEqual(roundTrip, original) {
+		t.Errorf("round trip mismatch\ngot:  %+v\nwant: %+v", roundTrip, original)
+	}
+}
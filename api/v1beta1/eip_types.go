@@ -0,0 +1,348 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ISP 线路类型
+// +kubebuilder:validation:Enum=BGP;BGP_PRO;ChinaTelecom;ChinaUnicom;ChinaMobile;ChinaTelecom_L2;ChinaUnicom_L2;ChinaMobile_L2;BGP_FinanceCloud;BGP_International
+type ISP string
+
+const (
+	ISPBGP              ISP = "BGP"
+	ISPBGPPro           ISP = "BGP_PRO"
+	ISPChinaTelecom     ISP = "ChinaTelecom"
+	ISPChinaUnicom      ISP = "ChinaUnicom"
+	ISPChinaMobile      ISP = "ChinaMobile"
+	ISPChinaTelecomL2   ISP = "ChinaTelecom_L2"
+	ISPChinaUnicomL2    ISP = "ChinaUnicom_L2"
+	ISPChinaMobileL2    ISP = "ChinaMobile_L2"
+	ISPBGPFinanceCloud  ISP = "BGP_FinanceCloud"
+	ISPBGPInternational ISP = "BGP_International"
+)
+
+// InternetChargeType 流量计费方式
+// +kubebuilder:validation:Enum=PayByBandwidth;PayByTraffic
+type InternetChargeType string
+
+const (
+	// InternetChargeTypePayByBandwidth 按固定带宽计费
+	InternetChargeTypePayByBandwidth InternetChargeType = "PayByBandwidth"
+	// InternetChargeTypePayByTraffic 按使用流量计费
+	InternetChargeTypePayByTraffic InternetChargeType = "PayByTraffic"
+)
+
+// InstanceChargeType 实例计费方式
+// +kubebuilder:validation:Enum=PrePaid;PostPaid
+type InstanceChargeType string
+
+const (
+	// InstanceChargeTypePrePaid 包年包月
+	InstanceChargeTypePrePaid InstanceChargeType = "PrePaid"
+	// InstanceChargeTypePostPaid 按量付费
+	InstanceChargeTypePostPaid InstanceChargeType = "PostPaid"
+)
+
+// ReleaseStrategy 定义EIP释放策略
+// +kubebuilder:validation:Enum=Never;OnDelete;OnDeleteIfCreated
+type ReleaseStrategy string
+
+const (
+	// ReleaseStrategyNever 永不释放EIP，即使删除CR也不释放
+	ReleaseStrategyNever ReleaseStrategy = "Never"
+	// ReleaseStrategyOnDelete 删除CR时释放EIP，包括导入的EIP
+	ReleaseStrategyOnDelete ReleaseStrategy = "OnDelete"
+	// ReleaseStrategyOnDeleteIfCreated 删除CR时只释放由operator创建的EIP
+	ReleaseStrategyOnDeleteIfCreated ReleaseStrategy = "OnDeleteIfCreated"
+)
+
+// EIPProvenance EIP的来源
+type EIPProvenance string
+
+const (
+	// EIPProvenanceCreated EIP由operator创建
+	EIPProvenanceCreated EIPProvenance = "Created"
+	// EIPProvenanceImported EIP通过allocationID导入
+	EIPProvenanceImported EIPProvenance = "Imported"
+)
+
+// DeletionPolicy 定义删除仍在使用中的EIP时的处理方式
+// +kubebuilder:validation:Enum=Block;DisassociateThenRelease;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyBlock EIP仍绑定实例时拒绝删除，finalizer等待解绑后再释放
+	DeletionPolicyBlock DeletionPolicy = "Block"
+	// DeletionPolicyDisassociateThenRelease 先解绑EIP再释放
+	DeletionPolicyDisassociateThenRelease DeletionPolicy = "DisassociateThenRelease"
+	// DeletionPolicyOrphan 只删除CR，保留云上EIP及其绑定关系
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// EIPState 云上EIP状态
+type EIPState string
+
+const (
+	EIPStateAssociating   EIPState = "Associating"
+	EIPStateUnassociating EIPState = "Unassociating"
+	EIPStateInUse         EIPState = "InUse"
+	EIPStateAvailable     EIPState = "Available"
+	EIPStateReleasing     EIPState = "Releasing"
+)
+
+// EIPBilling EIP计费配置
+type EIPBilling struct {
+	// InternetChargeType 流量计费方式
+	// +kubebuilder:default:=PayByTraffic
+	// +optional
+	InternetChargeType InternetChargeType `json:"internetChargeType,omitempty"`
+
+	// InstanceChargeType 实例计费方式
+	// +optional
+	InstanceChargeType InstanceChargeType `json:"instanceChargeType,omitempty"`
+
+	// Bandwidth EIP带宽，单位Mbps
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Bandwidth int32 `json:"bandwidth,omitempty"`
+}
+
+// EIPNetworking EIP网络配置
+type EIPNetworking struct {
+	// ISP 线路类型
+	// +optional
+	ISP ISP `json:"isp,omitempty"`
+
+	// PublicIPAddressPoolID 公网IP地址池ID
+	// +optional
+	PublicIPAddressPoolID string `json:"publicIPAddressPoolID,omitempty"`
+
+	// BandwidthPackageID 带宽包ID
+	// +optional
+	BandwidthPackageID string `json:"bandwidthPackageID,omitempty"`
+
+	// BandwidthPackageName 同命名空间下BandwidthPackage资源的名称，与bandwidthPackageID二选一
+	// +optional
+	BandwidthPackageName string `json:"bandwidthPackageName,omitempty"`
+}
+
+// EIPProtection EIP安全防护配置
+type EIPProtection struct {
+	// SecurityProtectionTypes 安全防护类型
+	// +optional
+	SecurityProtectionTypes []string `json:"securityProtectionTypes,omitempty"`
+
+	// DeletionProtection 云上删除保护，true时开启并保持开启，false时保持关闭，不设置时不管理
+	// +optional
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
+}
+
+// EIPObservedProtection 云上观测到的防护配置
+type EIPObservedProtection struct {
+	// DeletionProtection 删除保护状态
+	DeletionProtection bool `json:"deletionProtection"`
+}
+
+// EIPImportSource 查找要导入的已有EIP，ipAddress与tagSelector必须且只能指定一个
+type EIPImportSource struct {
+	// IPAddress 要导入的EIP公网IP地址
+	// +optional
+	IPAddress string `json:"ipAddress,omitempty"`
+
+	// TagSelector 按云上标签查找EIP，只能匹配到一个EIP
+	// +optional
+	TagSelector map[string]string `json:"tagSelector,omitempty"`
+}
+
+// EIPSpec defines the desired state of EIP
+type EIPSpec struct {
+	// AllocationID 指定已存在的EIP实例ID，如果指定则不会创建新的EIP
+	// +optional
+	AllocationID string `json:"allocationID,omitempty"`
+
+	// RegionID EIP所在地域，默认为控制器配置的地域，创建后不可修改
+	// +optional
+	RegionID string `json:"regionID,omitempty"`
+
+	// CloudAccountName 管理该EIP使用的CloudAccount，默认取命名空间注解eip.alibabacloud.com/cloud-account，
+	// 都未设置时使用控制器自身的凭证，创建后不可修改
+	// +optional
+	CloudAccountName string `json:"cloudAccountName,omitempty"`
+
+	// ImportFrom 按公网IP或云上标签查找并导入已有的EIP，与allocationID二选一
+	// +optional
+	ImportFrom *EIPImportSource `json:"importFrom,omitempty"`
+
+	// Name EIP名称
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Description EIP描述
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// ResourceGroupID 资源组ID
+	// +optional
+	ResourceGroupID string `json:"resourceGroupID,omitempty"`
+
+	// Billing 计费配置
+	// +optional
+	Billing EIPBilling `json:"billing,omitempty"`
+
+	// Networking 网络配置
+	// +optional
+	Networking EIPNetworking `json:"networking,omitempty"`
+
+	// Protection 安全防护配置
+	// +optional
+	Protection EIPProtection `json:"protection,omitempty"`
+
+	// Tags EIP标签，修改后会同步到云上，移除的标签会从云上删除
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// ReleaseStrategy EIP释放策略，默认只释放由operator创建的EIP
+	// +kubebuilder:default:=OnDeleteIfCreated
+	// +optional
+	ReleaseStrategy ReleaseStrategy `json:"releaseStrategy,omitempty"`
+
+	// DeletionPolicy 删除CR时EIP仍绑定在实例上的处理方式，仅在按ReleaseStrategy需要释放EIP时生效
+	// +kubebuilder:default:=Block
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// EIPObservedBilling 云上观测到的计费配置
+type EIPObservedBilling struct {
+	// InternetChargeType 流量计费方式
+	InternetChargeType InternetChargeType `json:"internetChargeType,omitempty"`
+
+	// InstanceChargeType 实例计费方式
+	InstanceChargeType InstanceChargeType `json:"instanceChargeType,omitempty"`
+
+	// Bandwidth 带宽，单位Mbps
+	Bandwidth int32 `json:"bandwidth,omitempty"`
+}
+
+// EIPObservedNetworking 云上观测到的网络配置
+type EIPObservedNetworking struct {
+	// ISP 线路类型
+	ISP ISP `json:"isp,omitempty"`
+
+	// PublicIPAddressPoolID 公网IP地址池ID
+	PublicIPAddressPoolID string `json:"publicIPAddressPoolID,omitempty"`
+
+	// BandwidthPackageID 带宽包ID
+	BandwidthPackageID string `json:"bandwidthPackageID,omitempty"`
+}
+
+// EIPAssociationInfo EIP当前的绑定信息
+type EIPAssociationInfo struct {
+	// InstanceID 绑定的实例ID
+	InstanceID string `json:"instanceID,omitempty"`
+
+	// InstanceType 绑定的实例类型
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// PrivateIPAddress 绑定的私网IP
+	PrivateIPAddress string `json:"privateIPAddress,omitempty"`
+}
+
+// EIPStatus defines the observed state of EIP
+type EIPStatus struct {
+	// AllocationID EIP实例ID
+	AllocationID string `json:"allocationID,omitempty"`
+
+	// EIPAddress EIP地址
+	EIPAddress string `json:"eipAddress,omitempty"`
+
+	// RegionID EIP所在地域
+	RegionID string `json:"regionID,omitempty"`
+
+	// CloudAccountName EIP所属的CloudAccount，为空表示使用控制器自身的凭证
+	CloudAccountName string `json:"cloudAccountName,omitempty"`
+
+	// State 云上EIP状态
+	State EIPState `json:"state,omitempty"`
+
+	// Provenance EIP来源，Created或Imported
+	Provenance EIPProvenance `json:"provenance,omitempty"`
+
+	// Name EIP名称
+	Name string `json:"name,omitempty"`
+
+	// Description EIP描述
+	Description string `json:"description,omitempty"`
+
+	// ResourceGroupID 资源组ID
+	ResourceGroupID string `json:"resourceGroupID,omitempty"`
+
+	// Billing 计费配置
+	Billing EIPObservedBilling `json:"billing,omitempty"`
+
+	// Networking 网络配置
+	Networking EIPObservedNetworking `json:"networking,omitempty"`
+
+	// Association 绑定信息
+	Association EIPAssociationInfo `json:"association,omitempty"`
+
+	// Protection 防护配置
+	Protection EIPObservedProtection `json:"protection,omitempty"`
+
+	// Tags 云上观测到的EIP标签，包括非operator添加的标签
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Conditions EIP状态条件
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastSyncTime 最后同步时间
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=eip
+//+kubebuilder:printcolumn:name="AllocationID",type=string,JSONPath=`.status.allocationID`
+//+kubebuilder:printcolumn:name="EIP Address",type=string,JSONPath=`.status.eipAddress`
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+//+kubebuilder:printcolumn:name="Bandwidth",type=integer,JSONPath=`.status.billing.bandwidth`
+//+kubebuilder:printcolumn:name="Provenance",type=string,JSONPath=`.status.provenance`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// EIP is the Schema for the eips API
+type EIP struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EIPSpec   `json:"spec,omitempty"`
+	Status EIPStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// EIPList contains a list of EIP
+type EIPList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EIP `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EIP{}, &EIPList{})
+}
@@ -41,7 +41,7 @@ kubectl apply -f config/default/namespace.yaml
 
 # 2. 安装 CRD
 info "2. 安装 CRD..."
-kubectl apply -f config/crd/eip.alibabacloud.com_eips.yaml
+kubectl apply -k config/crd
 
 # 3. 创建 RBAC 资源
 info "3. 创建 RBAC 资源..."
@@ -61,6 +61,32 @@ CA_BUNDLE=$(cat ca.crt | base64 | tr -d '\n')
 kubectl patch validatingwebhookconfiguration alibabacloud-eip-operator-validating-webhook-configuration \
   --type='json' -p="[{\"op\": \"add\", \"path\": \"/webhooks/0/clientConfig/caBundle\", \"value\":\"${CA_BUNDLE}\"}]"
 
+# 更新 MutatingWebhookConfiguration 的 caBundle
+echo "更新 MutatingWebhookConfiguration..."
+kubectl patch mutatingwebhookconfiguration alibabacloud-eip-operator-mutating-webhook-configuration \
+  --type='json' -p="[{\"op\": \"add\", \"path\": \"/webhooks/0/clientConfig/caBundle\", \"value\":\"${CA_BUNDLE}\"}]"
+
+# 为 EIP CRD 配置转换 Webhook 并写入 caBundle，配置与 config/crd/patches/webhook_in_eips.yaml 一致
+echo "配置 EIP CRD 转换 Webhook..."
+kubectl patch crd eips.eip.alibabacloud.com --type='merge' -p="{
+  \"spec\": {
+    \"conversion\": {
+      \"strategy\": \"Webhook\",
+      \"webhook\": {
+        \"conversionReviewVersions\": [\"v1\"],
+        \"clientConfig\": {
+          \"caBundle\": \"${CA_BUNDLE}\",
+          \"service\": {
+            \"name\": \"${SERVICE_NAME}\",
+            \"namespace\": \"${NAMESPACE}\",
+            \"path\": \"/convert\"
+          }
+        }
+      }
+    }
+  }
+}"
+
 # 清理
 cd -
 rm -rf "$TMP_DIR"
@@ -0,0 +1,42 @@
+#!/bin/bash
+
+# EIP 存储版本迁移
+# 存储版本目前为 v1alpha1（转换中心），将 +kubebuilder:storageversion 标记移到 v1beta1 并重新部署 CRD 后执行，
+# 将已有 EIP 按新的存储版本重新写入 etcd，然后从 CRD status.storedVersions 中移除旧版本，之后才能停止提供旧版本
+
+set -e
+
+CRD_NAME="eips.eip.alibabacloud.com"
+
+STORAGE_VERSION=$(kubectl get crd ${CRD_NAME} -o jsonpath='{.spec.versions[?(@.storage==true)].name}')
+STRATEGY=$(kubectl get crd ${CRD_NAME} -o jsonpath='{.spec.conversion.strategy}')
+STORED_VERSIONS=$(kubectl get crd ${CRD_NAME} -o jsonpath='{.status.storedVersions[*]}')
+echo "当前存储版本: ${STORAGE_VERSION}"
+echo "已存储版本: ${STORED_VERSIONS}"
+
+if [ "${STORED_VERSIONS}" == "${STORAGE_VERSION}" ]; then
+    echo "✅ 所有 EIP 均已按 ${STORAGE_VERSION} 存储，无需迁移"
+    exit 0
+fi
+
+if [ "${STRATEGY}" != "Webhook" ]; then
+    echo "❌ CRD 未配置转换 Webhook，请先执行 hack/generate-webhook-cert.sh"
+    exit 1
+fi
+
+# 空合并补丁不修改对象内容，但会让 API Server 以当前存储版本重新写入
+echo "重写 EIP 资源..."
+for eip in $(kubectl get eips.${STORAGE_VERSION}.eip.alibabacloud.com --all-namespaces \
+    -o jsonpath='{range .items[*]}{.metadata.namespace}/{.metadata.name}{"\n"}{end}'); do
+    namespace=${eip%%/*}
+    name=${eip#*/}
+    kubectl patch eips.${STORAGE_VERSION}.eip.alibabacloud.com "${name}" -n "${namespace}" \
+        --type='merge' -p='{}'
+done
+
+# 所有对象都已按新版本存储，更新 storedVersions
+echo "更新 storedVersions..."
+kubectl patch crd ${CRD_NAME} --subresource=status --type='merge' \
+    -p="{\"status\":{\"storedVersions\":[\"${STORAGE_VERSION}\"]}}"
+
+echo "✅ EIP 存储版本迁移完成: ${STORAGE_VERSION}"
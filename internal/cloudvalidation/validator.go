@@ -0,0 +1,251 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cloudvalidation validates cloud resources referenced by EIPs at admission time
+package cloudvalidation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+	"github.com/chrisliu1995/alibabacloud-eip-operator/pkg/config"
+)
+
+const (
+	// validateTimeout 单次准入校验调用阿里云接口的总超时，需小于webhook的超时时间
+	validateTimeout = 5 * time.Second
+)
+
+// isNotFoundError 检查是否为资源不存在错误
+func isNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+	errMsg := err.Error()
+	return strings.Contains(errMsg, "NotFound") ||
+		strings.Contains(errMsg, "EntityNotExists")
+}
+
+// lookup 一次云上查询的结果
+type lookup struct {
+	found  bool
+	status string
+	free   int
+}
+
+type cacheEntry struct {
+	result  lookup
+	expires time.Time
+}
+
+// ClientResolver 按EIP所属的账号选择阿里云客户端
+type ClientResolver interface {
+	// AccountFor 返回EIP所属的CloudAccount，为空表示控制器自身的凭证
+	AccountFor(ctx context.Context, eip *eipv1alpha1.EIP) (string, error)
+	// ForAccount 返回账号的客户端
+	ForAccount(name string) (aliyunclient.RegionalAPI, error)
+}
+
+// Validator 通过阿里云接口校验EIP引用的资源是否存在、是否在EIP所在地域以及是否有容量
+type Validator struct {
+	clients    ClientResolver
+	failClosed bool
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+var _ eipv1alpha1.EIPCloudValidator = &Validator{}
+
+// NewValidator 创建云上资源校验
+func NewValidator(clients ClientResolver, cfg config.CloudValidation) *Validator {
+	return &Validator{
+		clients:    clients,
+		failClosed: cfg.FailurePolicy == config.FailurePolicyFail,
+		ttl:        cfg.CacheTTL,
+		cache:      make(map[string]cacheEntry),
+	}
+}
+
+// ValidateEIP 校验EIP引用的云上资源，只校验创建时设置或本次修改的引用
+func (v *Validator) ValidateEIP(ctx context.Context, eip, old *eipv1alpha1.EIP) (admission.Warnings, field.ErrorList) {
+	ctx, cancel := context.WithTimeout(ctx, validateTimeout)
+	defer cancel()
+
+	var warnings admission.Warnings
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	var oldSpec eipv1alpha1.EIPSpec
+	var oldAllocationID string
+	if old != nil {
+		oldSpec = old.Spec
+		oldAllocationID = old.Status.AllocationID
+	}
+	changed := func(value, oldValue string) bool {
+		return value != "" && (old == nil || value != oldValue)
+	}
+
+	// 引用的资源需要与EIP在同一账号和地域，缓存按账号和地域区分
+	account, err := v.clients.AccountFor(ctx, eip)
+	if err != nil {
+		return v.handleError(specPath.Child("cloudAccountName"), err, warnings, allErrs)
+	}
+	clients, err := v.clients.ForAccount(account)
+	if err != nil {
+		return v.handleError(specPath.Child("cloudAccountName"), err, warnings, allErrs)
+	}
+	regionID := eip.Spec.RegionID
+	if regionID == "" {
+		regionID = clients.DefaultRegion()
+	}
+	api, err := clients.ForRegion(regionID)
+	if err != nil {
+		return v.handleError(specPath.Child("regionID"), err, warnings, allErrs)
+	}
+	cachePrefix := account + "/" + regionID
+
+	// 控制器创建EIP后回填的allocationID无需校验
+	if id := eip.Spec.AllocationID; changed(id, oldSpec.AllocationID) && id != oldAllocationID {
+		path := specPath.Child("allocationID")
+		result, err := v.get(ctx, cachePrefix+"/eip/"+id, func(ctx context.Context) (lookup, error) {
+			eips, err := api.DescribeEipAddresses(ctx, id, "", "", "")
+			if err != nil || len(eips) == 0 {
+				return lookup{}, err
+			}
+			return lookup{found: true, status: eips[0].Status}, nil
+		})
+		switch {
+		case err != nil:
+			warnings, allErrs = v.handleError(path, err, warnings, allErrs)
+		case !result.found:
+			allErrs = append(allErrs, field.Invalid(path, id, fmt.Sprintf("EIP 在地域 %s 中不存在", regionID)))
+		case result.status == "Releasing":
+			warnings = append(warnings, fmt.Sprintf("%s: EIP %s 正在释放", path, id))
+		}
+	}
+
+	if id := eip.Spec.BandwidthPackageID; changed(id, oldSpec.BandwidthPackageID) {
+		path := specPath.Child("bandwidthPackageID")
+		result, err := v.get(ctx, cachePrefix+"/cbwp/"+id, func(ctx context.Context) (lookup, error) {
+			pkgs, err := api.DescribeCommonBandwidthPackages(ctx, id)
+			if err != nil || len(pkgs) == 0 {
+				return lookup{}, err
+			}
+			return lookup{found: true, status: pkgs[0].Status}, nil
+		})
+		switch {
+		case err != nil:
+			warnings, allErrs = v.handleError(path, err, warnings, allErrs)
+		case !result.found:
+			allErrs = append(allErrs, field.Invalid(path, id, fmt.Sprintf("共享带宽包在地域 %s 中不存在", regionID)))
+		case result.status != aliyunclient.BandwidthPackageStatusAvailable:
+			warnings = append(warnings, fmt.Sprintf("%s: 共享带宽包 %s 当前状态为 %s", path, id, result.status))
+		}
+	}
+
+	if id := eip.Spec.PublicIPAddressPoolID; changed(id, oldSpec.PublicIPAddressPoolID) {
+		path := specPath.Child("publicIPAddressPoolID")
+		result, err := v.get(ctx, cachePrefix+"/pool/"+id, func(ctx context.Context) (lookup, error) {
+			blocks, err := api.ListPublicIpAddressPoolCidrBlocks(ctx, id)
+			if err != nil {
+				return lookup{}, err
+			}
+			result := lookup{found: true}
+			for _, block := range blocks {
+				result.free += block.TotalIPNum - block.UsedIPNum
+			}
+			return result, nil
+		})
+		switch {
+		case err != nil:
+			warnings, allErrs = v.handleError(path, err, warnings, allErrs)
+		case !result.found:
+			allErrs = append(allErrs, field.Invalid(path, id, fmt.Sprintf("公网IP地址池在地域 %s 中不存在", regionID)))
+		case result.free <= 0 && eip.Spec.AllocationID == "" && eip.Spec.ImportFrom == nil:
+			allErrs = append(allErrs, field.Forbidden(path, fmt.Sprintf("公网IP地址池 %s 已没有可分配的IP", id)))
+		}
+	}
+
+	if id := eip.Spec.ResourceGroupID; changed(id, oldSpec.ResourceGroupID) {
+		path := specPath.Child("resourceGroupID")
+		result, err := v.get(ctx, account+"/rg/"+id, func(ctx context.Context) (lookup, error) {
+			group, err := api.GetResourceGroup(ctx, id)
+			if err != nil {
+				return lookup{}, err
+			}
+			return lookup{found: true, status: group.Status}, nil
+		})
+		switch {
+		case err != nil:
+			warnings, allErrs = v.handleError(path, err, warnings, allErrs)
+		case !result.found:
+			allErrs = append(allErrs, field.Invalid(path, id, "资源组不存在"))
+		case result.status != aliyunclient.ResourceGroupStatusOK:
+			warnings = append(warnings, fmt.Sprintf("%s: 资源组 %s 当前状态为 %s", path, id, result.status))
+		}
+	}
+
+	return warnings, allErrs
+}
+
+// handleError 按失败策略处理无法完成的查询，Fail时拒绝请求，Ignore时放行并返回警告
+func (v *Validator) handleError(path *field.Path, err error, warnings admission.Warnings, allErrs field.ErrorList) (admission.Warnings, field.ErrorList) {
+	if v.failClosed {
+		return warnings, append(allErrs, field.InternalError(path, fmt.Errorf("无法通过阿里云接口校验: %w", err)))
+	}
+	return append(warnings, fmt.Sprintf("%s: 无法通过阿里云接口校验，已跳过: %v", path, err)), allErrs
+}
+
+// get 返回缓存的查询结果，过期或不存在时调用fetch，资源不存在的错误视为查询成功
+func (v *Validator) get(ctx context.Context, key string, fetch func(ctx context.Context) (lookup, error)) (lookup, error) {
+	now := time.Now()
+
+	v.mu.Lock()
+	entry, ok := v.cache[key]
+	v.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.result, nil
+	}
+
+	result, err := fetch(ctx)
+	if err != nil && !isNotFoundError(err) {
+		return lookup{}, err
+	}
+	if err != nil {
+		result = lookup{}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for k, e := range v.cache {
+		if now.After(e.expires) {
+			delete(v.cache, k)
+		}
+	}
+	v.cache[key] = cacheEntry{result: result, expires: now.Add(v.ttl)}
+
+	return result, nil
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudvalidation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+	"github.com/chrisliu1995/alibabacloud-eip-operator/pkg/config"
+)
+
+// fakeAPI answers the lookups of the validator, err fails every call
+type fakeAPI struct {
+	aliyunclient.API
+
+	eips  map[string]string
+	pools map[string][]aliyunclient.PublicIPAddressPoolCidrBlock
+	err   error
+	calls int
+}
+
+func (f *fakeAPI) DescribeEipAddresses(ctx context.Context, allocationID, eipAddress, associatedInstanceID, associatedInstanceType string) ([]aliyunclient.EIPAddress, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	status, ok := f.eips[allocationID]
+	if !ok {
+		return nil, nil
+	}
+	return []aliyunclient.EIPAddress{{AllocationID: allocationID, Status: status}}, nil
+}
+
+func (f *fakeAPI) ListPublicIpAddressPoolCidrBlocks(ctx context.Context, poolID string) ([]aliyunclient.PublicIPAddressPoolCidrBlock, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	blocks, ok := f.pools[poolID]
+	if !ok {
+		return nil, errors.New("ResourceNotFound.PublicIpAddressPool")
+	}
+	return blocks, nil
+}
+
+// fakeResolver hands out the same API for the default credentials in every region
+type fakeResolver struct {
+	api aliyunclient.API
+}
+
+func (r *fakeResolver) AccountFor(ctx context.Context, eip *eipv1alpha1.EIP) (string, error) {
+	return "", nil
+}
+
+func (r *fakeResolver) ForAccount(name string) (aliyunclient.RegionalAPI, error) {
+	return aliyunclient.NewClientCache("cn-hangzhou", func(regionID string) (aliyunclient.API, error) {
+		return r.api, nil
+	}), nil
+}
+
+func newTestValidator(api *fakeAPI, failurePolicy string, ttl time.Duration) *Validator {
+	return NewValidator(&fakeResolver{api: api}, config.CloudValidation{Enabled: true, FailurePolicy: failurePolicy, CacheTTL: ttl})
+}
+
+func TestValidateEIP(t *testing.T) {
+	api := &fakeAPI{
+		eips: map[string]string{"eip-1": "Available", "eip-2": "Releasing"},
+		pools: map[string][]aliyunclient.PublicIPAddressPoolCidrBlock{
+			"pool-free": {{TotalIPNum: 16, UsedIPNum: 1}},
+			"pool-full": {{TotalIPNum: 16, UsedIPNum: 16}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		spec         eipv1alpha1.EIPSpec
+		wantErr      field.ErrorType
+		wantWarnings int
+	}{
+		{
+			name: "existing EIP",
+			spec: eipv1alpha1.EIPSpec{AllocationID: "eip-1"},
+		},
+		{
+			name:    "missing EIP",
+			spec:    eipv1alpha1.EIPSpec{AllocationID: "eip-missing"},
+			wantErr: field.ErrorTypeInvalid,
+		},
+		{
+			name:         "releasing EIP",
+			spec:         eipv1alpha1.EIPSpec{AllocationID: "eip-2"},
+			wantWarnings: 1,
+		},
+		{
+			name: "pool with free IPs",
+			spec: eipv1alpha1.EIPSpec{PublicIPAddressPoolID: "pool-free"},
+		},
+		{
+			name:    "exhausted pool",
+			spec:    eipv1alpha1.EIPSpec{PublicIPAddressPoolID: "pool-full"},
+			wantErr: field.ErrorTypeForbidden,
+		},
+		{
+			name:    "missing pool",
+			spec:    eipv1alpha1.EIPSpec{PublicIPAddressPoolID: "pool-missing"},
+			wantErr: field.ErrorTypeInvalid,
+		},
+	}
+
+	v := newTestValidator(api, config.FailurePolicyIgnore, time.Minute)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, errs := v.ValidateEIP(context.Background(), &eipv1alpha1.EIP{Spec: tt.spec}, nil)
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("warnings = %v, want %d", warnings, tt.wantWarnings)
+			}
+			switch {
+			case tt.wantErr == "" && len(errs) > 0:
+				t.Errorf("errors = %v, want none", errs)
+			case tt.wantErr != "" && (len(errs) != 1 || errs[0].Type != tt.wantErr):
+				t.Errorf("errors = %v, want one %s", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEIPSkipsUnchangedReferences(t *testing.T) {
+	api := &fakeAPI{eips: map[string]string{}}
+	v := newTestValidator(api, config.FailurePolicyFail, time.Minute)
+
+	old := &eipv1alpha1.EIP{Spec: eipv1alpha1.EIPSpec{AllocationID: "eip-gone"}}
+	if _, errs := v.ValidateEIP(context.Background(), old.DeepCopy(), old); len(errs) > 0 {
+		t.Errorf("errors = %v, want none for an unchanged reference", errs)
+	}
+	if api.calls != 0 {
+		t.Errorf("API called %d times, want 0", api.calls)
+	}
+}
+
+func TestValidateEIPCache(t *testing.T) {
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		wantCalls int
+	}{
+		{
+			name:      "lookups are cached",
+			ttl:       time.Minute,
+			wantCalls: 1,
+		},
+		{
+			name:      "expired lookups are repeated",
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{eips: map[string]string{"eip-1": "Available"}}
+			v := newTestValidator(api, config.FailurePolicyIgnore, tt.ttl)
+			eip := &eipv1alpha1.EIP{Spec: eipv1alpha1.EIPSpec{AllocationID: "eip-1"}}
+
+			for i := 0; i < 2; i++ {
+				if _, errs := v.ValidateEIP(context.Background(), eip, nil); len(errs) > 0 {
+					t.Fatalf("errors = %v, want none", errs)
+				}
+			}
+			if api.calls != tt.wantCalls {
+				t.Errorf("API called %d times, want %d", api.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestValidateEIPFailurePolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		failurePolicy string
+		wantErr       bool
+	}{
+		{
+			name:          "fail open",
+			failurePolicy: config.FailurePolicyIgnore,
+		},
+		{
+			name:          "fail closed",
+			failurePolicy: config.FailurePolicyFail,
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{err: errors.New("Throttling.User")}
+			v := newTestValidator(api, tt.failurePolicy, time.Minute)
+			eip := &eipv1alpha1.EIP{Spec: eipv1alpha1.EIPSpec{AllocationID: "eip-1"}}
+
+			warnings, errs := v.ValidateEIP(context.Background(), eip, nil)
+			if tt.wantErr {
+				if len(errs) != 1 || errs[0].Type != field.ErrorTypeInternal {
+					t.Errorf("errors = %v, want one internal error", errs)
+				}
+				return
+			}
+			if len(errs) > 0 || len(warnings) != 1 {
+				t.Errorf("errors = %v, warnings = %v, want a warning only", errs, warnings)
+			}
+
+			// Failed lookups are not cached
+			v.ValidateEIP(context.Background(), eip, nil)
+			if api.calls != 2 {
+				t.Errorf("API called %d times, want 2", api.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+// allocationIDConflicts returns the other EIP objects, in any namespace, that reference the same cloud EIP
+func allocationIDConflicts(ctx context.Context, c client.Reader, eip *eipv1alpha1.EIP, allocationID string) ([]eipv1alpha1.EIP, error) {
+	if allocationID == "" {
+		return nil, nil
+	}
+
+	eips := &eipv1alpha1.EIPList{}
+	if err := c.List(ctx, eips, client.MatchingFields{eipv1alpha1.EIPAllocationIDField: allocationID}); err != nil {
+		return nil, err
+	}
+
+	var others []eipv1alpha1.EIP
+	for _, other := range eips.Items {
+		if other.Namespace == eip.Namespace && other.Name == eip.Name {
+			continue
+		}
+		others = append(others, other)
+	}
+	return others, nil
+}
+
+// holdsAllocationID reports whether eip owns the lock on a shared cloud EIP.
+// The oldest object keeps managing it, ties are broken by namespace and name.
+func holdsAllocationID(eip *eipv1alpha1.EIP, others []eipv1alpha1.EIP) bool {
+	for i := range others {
+		other := &others[i]
+		if other.CreationTimestamp.Before(&eip.CreationTimestamp) {
+			return false
+		}
+		if other.CreationTimestamp.Equal(&eip.CreationTimestamp) &&
+			other.Namespace+"/"+other.Name < eip.Namespace+"/"+eip.Name {
+			return false
+		}
+	}
+	return true
+}
+
+// eipNames formats EIP objects as namespace/name for messages
+func eipNames(eips []eipv1alpha1.EIP) string {
+	names := make([]string, 0, len(eips))
+	for _, eip := range eips {
+		names = append(names, eip.Namespace+"/"+eip.Name)
+	}
+	return strings.Join(names, ", ")
+}
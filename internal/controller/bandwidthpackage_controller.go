@@ -0,0 +1,403 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+const (
+	bandwidthPackageFinalizer = "eip.alibabacloud.com/bandwidthpackage-finalizer"
+
+	// resourceTypeBandwidthPackage 标签接口中共享带宽包的资源类型
+	resourceTypeBandwidthPackage = "COMMONBANDWIDTHPACKAGE"
+
+	// Reasons
+	reasonBandwidthPackageNotFound = "NotFound"
+	reasonBandwidthPackageInUse    = "InUse"
+)
+
+// BandwidthPackageReconciler reconciles a BandwidthPackage object
+type BandwidthPackageReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Record   record.EventRecorder
+	Accounts *CloudAccountClients
+	// ClusterID 写入归属标签的集群标识
+	ClusterID string
+}
+
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=bandwidthpackages,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=bandwidthpackages/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=bandwidthpackages/finalizers,verbs=update
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eips,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop
+func (r *BandwidthPackageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	pkg := &eipv1alpha1.BandwidthPackage{}
+	err := r.Get(ctx, req.NamespacedName, pkg)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	var clients aliyunclient.RegionalAPI
+	account, err := r.Accounts.accountFor(ctx, pkg.Namespace, pkg.Spec.CloudAccountName, pkg.Status.CloudAccountName, pkg.Status.BandwidthPackageID != "")
+	if err == nil {
+		pkg.Status.CloudAccountName = account
+		clients, err = r.Accounts.ForAccount(account)
+	}
+	if err != nil {
+		// Nothing was created in the cloud yet, otherwise deletion also waits for the account
+		if !pkg.DeletionTimestamp.IsZero() && pkg.Status.BandwidthPackageID == "" {
+			controllerutil.RemoveFinalizer(pkg, bandwidthPackageFinalizer)
+			return ctrl.Result{}, r.Update(ctx, pkg)
+		}
+		l.Info("cloud account is not ready", "cloudAccount", account, "error", err.Error())
+		r.setCondition(pkg, conditionTypeReady, metav1.ConditionFalse, reasonCloudAccountNotReady, err.Error())
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, pkg)
+	}
+	pkg.Status.RegionID = resourceRegion(pkg.Spec.RegionID, pkg.Status.RegionID, clients)
+	api, err := clients.ForRegion(pkg.Status.RegionID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Check if the BandwidthPackage instance is marked to be deleted
+	if !pkg.ObjectMeta.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(pkg, bandwidthPackageFinalizer) {
+			done, err := r.finalizeBandwidthPackage(ctx, api, pkg)
+			if err != nil {
+				if isThrottlingError(err) {
+					return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterThrottle}, nil
+				}
+				return ctrl.Result{}, err
+			}
+			if !done {
+				return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, nil
+			}
+
+			controllerutil.RemoveFinalizer(pkg, bandwidthPackageFinalizer)
+			if err := r.Update(ctx, pkg); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer if not present
+	if !controllerutil.ContainsFinalizer(pkg, bandwidthPackageFinalizer) {
+		controllerutil.AddFinalizer(pkg, bandwidthPackageFinalizer)
+		if err := r.Update(ctx, pkg); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	result, err := r.reconcileBandwidthPackage(ctx, api, pkg)
+	if err != nil {
+		if isThrottlingError(err) {
+			l.Info("API throttled, will retry later")
+			r.setCondition(pkg, conditionTypeReady, metav1.ConditionFalse, reasonThrottled, "API throttled, retrying later")
+			_ = r.updateStatus(ctx, pkg)
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterThrottle}, nil
+		}
+		l.Error(err, "failed to reconcile BandwidthPackage")
+		r.Record.Eventf(pkg, "Warning", "ReconcileFailed", "Failed to reconcile BandwidthPackage: %v", err)
+		r.setCondition(pkg, conditionTypeReady, metav1.ConditionFalse, reasonSyncFailed, err.Error())
+		_ = r.updateStatus(ctx, pkg)
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
+	}
+
+	return result, nil
+}
+
+// reconcileBandwidthPackage creates, resizes and syncs the bandwidth package
+func (r *BandwidthPackageReconciler) reconcileBandwidthPackage(ctx context.Context, api aliyunclient.API, pkg *eipv1alpha1.BandwidthPackage) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	// If BandwidthPackageID is not set, create a new bandwidth package
+	if pkg.Spec.BandwidthPackageID == "" {
+		if pkg.Status.BandwidthPackageID == "" {
+			l.Info("creating new bandwidth package")
+			r.setCondition(pkg, conditionTypeProgressing, metav1.ConditionTrue, reasonCreating, "Creating new bandwidth package")
+			if err := r.updateStatus(ctx, pkg); err != nil {
+				return ctrl.Result{}, err
+			}
+
+			packageID, err := r.createBandwidthPackage(ctx, api, pkg)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+
+			pkg.Status.BandwidthPackageID = packageID
+			pkg.Status.Provenance = eipv1alpha1.EIPProvenanceCreated
+			if err := r.updateStatus(ctx, pkg); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.Record.Eventf(pkg, "Normal", "Created", "Created bandwidth package: %s", packageID)
+			r.setCondition(pkg, conditionTypeProgressing, metav1.ConditionFalse, reasonCreated, "Bandwidth package created")
+		}
+
+		pkg.Spec.BandwidthPackageID = pkg.Status.BandwidthPackageID
+		if err := r.Update(ctx, pkg); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if pkg.Status.BandwidthPackageID == "" && pkg.Status.Provenance == "" {
+		// The package was referenced by ID before this object ever created one
+		pkg.Status.Provenance = eipv1alpha1.EIPProvenanceImported
+	}
+
+	info, err := r.describeBandwidthPackage(ctx, api, pkg.Spec.BandwidthPackageID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if info == nil {
+		r.setCondition(pkg, conditionTypeReady, metav1.ConditionFalse, reasonBandwidthPackageNotFound,
+			fmt.Sprintf("Bandwidth package %s not found", pkg.Spec.BandwidthPackageID))
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, pkg)
+	}
+
+	// Update bandwidth if needed
+	bandwidth := strconv.Itoa(int(pkg.Spec.Bandwidth))
+	if info.Status == aliyunclient.BandwidthPackageStatusAvailable && info.Bandwidth != bandwidth {
+		l.Info("updating bandwidth package bandwidth", "from", info.Bandwidth, "to", bandwidth)
+		if err := api.ModifyCommonBandwidthPackageSpec(ctx, info.BandwidthPackageID, bandwidth); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.Record.Eventf(pkg, "Normal", "Updated", "Updated bandwidth package bandwidth to %s", bandwidth)
+
+		if info, err = r.describeBandwidthPackage(ctx, api, pkg.Spec.BandwidthPackageID); err != nil || info == nil {
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
+		}
+	}
+
+	if err := r.syncStatus(ctx, pkg, info); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.setCondition(pkg, conditionTypeReady, metav1.ConditionTrue, "Available", "Bandwidth package is ready")
+	r.setCondition(pkg, conditionTypeSynced, metav1.ConditionTrue, "Synced", "Bandwidth package synced successfully")
+	if err := r.updateStatus(ctx, pkg); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+// createBandwidthPackage creates the bandwidth package, or recovers the one a previous reconcile created for this object
+func (r *BandwidthPackageReconciler) createBandwidthPackage(ctx context.Context, api aliyunclient.API, pkg *eipv1alpha1.BandwidthPackage) (string, error) {
+	l := log.FromContext(ctx)
+
+	// A previous reconcile may have created the package and failed before persisting its ID
+	owned, err := api.DescribeCommonBandwidthPackagesByTags(ctx, ownerUIDSelector(pkg))
+	if err != nil {
+		return "", err
+	}
+	if len(owned) > 0 {
+		if len(owned) > 1 {
+			extra := make([]string, 0, len(owned)-1)
+			for _, info := range owned[1:] {
+				extra = append(extra, info.BandwidthPackageID)
+			}
+			l.Info("found multiple bandwidth packages tagged for this object, recovering the first one", "count", len(owned), "extra", extra)
+			r.Record.Eventf(pkg, "Warning", "MultipleOwned", "Found %d bandwidth packages tagged for this object, recovering %s, release the extra ones manually: %s",
+				len(owned), owned[0].BandwidthPackageID, strings.Join(extra, ", "))
+		}
+		l.Info("recovered previously created bandwidth package", "bandwidthPackageID", owned[0].BandwidthPackageID)
+		r.Record.Eventf(pkg, "Normal", "Recovered", "Recovered previously created bandwidth package: %s", owned[0].BandwidthPackageID)
+		return owned[0].BandwidthPackageID, nil
+	}
+
+	packageID, err := api.CreateCommonBandwidthPackage(ctx, &aliyunclient.BandwidthPackageOptions{
+		Bandwidth:               strconv.Itoa(int(pkg.Spec.Bandwidth)),
+		InternetChargeType:      pkg.Spec.InternetChargeType,
+		ISP:                     pkg.Spec.ISP,
+		ResourceGroupID:         pkg.Spec.ResourceGroupID,
+		Name:                    pkg.Spec.Name,
+		Description:             pkg.Spec.Description,
+		SecurityProtectionTypes: pkg.Spec.SecurityProtectionTypes,
+		ClientToken:             clientTokenFor(pkg),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// The ownership tag is what makes the package recoverable, the retry reuses the ClientToken and gets the same package back
+	tags := ownershipTags(pkg, r.ClusterID)
+	for k, v := range operatorTags(r.ClusterID) {
+		tags[k] = v
+	}
+	if err := api.TagResources(ctx, resourceTypeBandwidthPackage, []string{packageID}, tags); err != nil {
+		l.Error(err, "failed to tag bandwidth package", "bandwidthPackageID", packageID)
+		return "", err
+	}
+
+	return packageID, nil
+}
+
+// describeBandwidthPackage returns the bandwidth package, nil if it does not exist
+func (r *BandwidthPackageReconciler) describeBandwidthPackage(ctx context.Context, api aliyunclient.API, packageID string) (*aliyunclient.BandwidthPackage, error) {
+	pkgs, err := api.DescribeCommonBandwidthPackages(ctx, packageID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pkgs {
+		if pkgs[i].BandwidthPackageID == packageID {
+			return &pkgs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// syncStatus copies the cloud side state into status and resolves members to EIP objects
+func (r *BandwidthPackageReconciler) syncStatus(ctx context.Context, pkg *eipv1alpha1.BandwidthPackage, info *aliyunclient.BandwidthPackage) error {
+	eips := &eipv1alpha1.EIPList{}
+	if err := r.List(ctx, eips, client.InNamespace(pkg.Namespace)); err != nil {
+		return err
+	}
+	eipNames := make(map[string]string, len(eips.Items))
+	for _, eip := range eips.Items {
+		if eip.Status.AllocationID != "" {
+			eipNames[eip.Status.AllocationID] = eip.Name
+		}
+	}
+
+	members := make([]eipv1alpha1.BandwidthPackageMember, 0, len(info.PublicIPAddresses))
+	for _, ip := range info.PublicIPAddresses {
+		members = append(members, eipv1alpha1.BandwidthPackageMember{
+			AllocationID: ip.AllocationID,
+			EIPAddress:   ip.IPAddress,
+			EIPName:      eipNames[ip.AllocationID],
+		})
+	}
+
+	pkg.Status.BandwidthPackageID = info.BandwidthPackageID
+	pkg.Status.Status = info.Status
+	pkg.Status.Bandwidth = info.Bandwidth
+	pkg.Status.InternetChargeType = info.InternetChargeType
+	pkg.Status.ISP = info.ISP
+	pkg.Status.Members = members
+	pkg.Status.MemberCount = int32(len(members))
+
+	now := metav1.Now()
+	pkg.Status.LastSyncTime = &now
+	return nil
+}
+
+// finalizeBandwidthPackage waits for referencing EIPs to go away, then releases the bandwidth package.
+// It returns false while EIPs still reference the package.
+func (r *BandwidthPackageReconciler) finalizeBandwidthPackage(ctx context.Context, api aliyunclient.API, pkg *eipv1alpha1.BandwidthPackage) (bool, error) {
+	l := log.FromContext(ctx)
+
+	eips := &eipv1alpha1.EIPList{}
+	if err := r.List(ctx, eips, client.InNamespace(pkg.Namespace),
+		client.MatchingFields{eipBandwidthPackageNameField: pkg.Name}); err != nil {
+		return false, err
+	}
+	if len(eips.Items) > 0 {
+		r.setCondition(pkg, conditionTypeReady, metav1.ConditionFalse, reasonBandwidthPackageInUse,
+			fmt.Sprintf("Bandwidth package is still referenced by %d EIPs", len(eips.Items)))
+		return false, r.updateStatus(ctx, pkg)
+	}
+
+	if !releasedOnDelete(pkg.Spec.ReleaseStrategy, pkg.Status.Provenance) || pkg.Status.BandwidthPackageID == "" {
+		l.Info("skipping bandwidth package release", "releaseStrategy", pkg.Spec.ReleaseStrategy, "provenance", pkg.Status.Provenance)
+		r.Record.Event(pkg, "Normal", "Skipped", "Skipped bandwidth package release due to ReleaseStrategy")
+		return true, nil
+	}
+
+	info, err := r.describeBandwidthPackage(ctx, api, pkg.Status.BandwidthPackageID)
+	if err != nil {
+		return false, err
+	}
+	if info == nil {
+		l.Info("bandwidth package not found, assuming already released", "packageID", pkg.Status.BandwidthPackageID)
+		return true, nil
+	}
+
+	l.Info("releasing bandwidth package", "packageID", pkg.Status.BandwidthPackageID)
+	if err := api.DeleteCommonBandwidthPackage(ctx, pkg.Status.BandwidthPackageID); err != nil {
+		r.Record.Eventf(pkg, "Warning", "ReleaseFailed", "Failed to release bandwidth package: %v", err)
+		return false, err
+	}
+	r.Record.Eventf(pkg, "Normal", "Released", "Released bandwidth package: %s", pkg.Status.BandwidthPackageID)
+	return true, nil
+}
+
+// setCondition sets a condition on the BandwidthPackage
+func (r *BandwidthPackageReconciler) setCondition(pkg *eipv1alpha1.BandwidthPackage, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: pkg.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	apimeta.SetStatusCondition(&pkg.Status.Conditions, condition)
+}
+
+// updateStatus updates the BandwidthPackage status
+func (r *BandwidthPackageReconciler) updateStatus(ctx context.Context, pkg *eipv1alpha1.BandwidthPackage) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return r.Status().Update(ctx, pkg)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// The EIP bandwidthPackageName index is registered by the EIP controller.
+func (r *BandwidthPackageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&eipv1alpha1.BandwidthPackage{}).
+		Watches(&eipv1alpha1.EIP{}, handler.EnqueueRequestsFromMapFunc(bandwidthPackageForEIP)).
+		Complete(r)
+}
+
+// bandwidthPackageForEIP maps an EIP to the BandwidthPackage it references by name
+func bandwidthPackageForEIP(ctx context.Context, obj client.Object) []reconcile.Request {
+	name := obj.(*eipv1alpha1.EIP).Spec.BandwidthPackageName
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+func TestBandwidthPackageLifecycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy eipv1alpha1.ReleaseStrategy
+		// importID references an existing package, recover tags one for this object before the first reconcile
+		importID string
+		recover  bool
+		// referenced keeps an EIP pointing at the package during deletion
+		referenced bool
+
+		wantCreated    bool
+		wantProvenance eipv1alpha1.EIPProvenance
+		wantReleased   bool
+		wantGone       bool
+	}{
+		{
+			name:           "created package is released by default",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDeleteIfCreated,
+			wantCreated:    true,
+			wantProvenance: eipv1alpha1.EIPProvenanceCreated,
+			wantReleased:   true,
+			wantGone:       true,
+		},
+		{
+			name:           "imported package is kept by default",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDeleteIfCreated,
+			importID:       "cbwp-imported",
+			wantProvenance: eipv1alpha1.EIPProvenanceImported,
+			wantGone:       true,
+		},
+		{
+			name:           "imported package is released on OnDelete",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDelete,
+			importID:       "cbwp-imported",
+			wantProvenance: eipv1alpha1.EIPProvenanceImported,
+			wantReleased:   true,
+			wantGone:       true,
+		},
+		{
+			name:           "created package is kept on Never",
+			strategy:       eipv1alpha1.ReleaseStrategyNever,
+			wantCreated:    true,
+			wantProvenance: eipv1alpha1.EIPProvenanceCreated,
+			wantGone:       true,
+		},
+		{
+			name:           "package tagged for this object is recovered",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDeleteIfCreated,
+			recover:        true,
+			wantProvenance: eipv1alpha1.EIPProvenanceCreated,
+			wantReleased:   true,
+			wantGone:       true,
+		},
+		{
+			name:           "referenced package waits for its EIPs",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDeleteIfCreated,
+			referenced:     true,
+			wantCreated:    true,
+			wantProvenance: eipv1alpha1.EIPProvenanceCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := newFakeCloud()
+			pkg := &eipv1alpha1.BandwidthPackage{
+				ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default", UID: "bwp-uid"},
+				Spec: eipv1alpha1.BandwidthPackageSpec{
+					BandwidthPackageID: tt.importID,
+					Bandwidth:          100,
+					ReleaseStrategy:    tt.strategy,
+				},
+			}
+			if tt.importID != "" {
+				cloud.packages[tt.importID] = &aliyunclient.BandwidthPackage{
+					BandwidthPackageID: tt.importID, Status: aliyunclient.BandwidthPackageStatusAvailable, Bandwidth: "100",
+				}
+			}
+			if tt.recover {
+				cloud.packages["cbwp-lost"] = &aliyunclient.BandwidthPackage{
+					BandwidthPackageID: "cbwp-lost", Status: aliyunclient.BandwidthPackageStatusAvailable, Bandwidth: "100",
+				}
+				cloud.tags["cbwp-lost"] = ownerUIDSelector(pkg)
+			}
+			objs := []client.Object{testNamespace(), pkg}
+			if tt.referenced {
+				objs = append(objs, &eipv1alpha1.EIP{
+					ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+					Spec:       eipv1alpha1.EIPSpec{BandwidthPackageName: pkg.Name},
+				})
+			}
+
+			c := fakeClientBuilder(objs...).
+				WithIndex(&eipv1alpha1.EIP{}, eipBandwidthPackageNameField, func(obj client.Object) []string {
+					return []string{obj.(*eipv1alpha1.EIP).Spec.BandwidthPackageName}
+				}).Build()
+			r := &BandwidthPackageReconciler{
+				Client:   c,
+				Scheme:   c.Scheme(),
+				Record:   record.NewFakeRecorder(100),
+				Accounts: accountsFor(c, cloud),
+			}
+
+			if err := reconcileN(ctx, r, pkg, 1); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(pkg), pkg); err != nil {
+				t.Fatal(err)
+			}
+			if got := cloud.called("CreateCommonBandwidthPackage") == 1; got != tt.wantCreated {
+				t.Errorf("created = %v, want %v", got, tt.wantCreated)
+			}
+			if pkg.Status.BandwidthPackageID == "" || pkg.Spec.BandwidthPackageID != pkg.Status.BandwidthPackageID {
+				t.Errorf("spec ID %q, status ID %q, want both set", pkg.Spec.BandwidthPackageID, pkg.Status.BandwidthPackageID)
+			}
+			if pkg.Status.Provenance != tt.wantProvenance {
+				t.Errorf("provenance = %s, want %s", pkg.Status.Provenance, tt.wantProvenance)
+			}
+			if tt.wantCreated && !cloud.tagged(pkg.Status.BandwidthPackageID, ownerUIDSelector(pkg)) {
+				t.Errorf("created package not tagged with its owner: %v", cloud.tags[pkg.Status.BandwidthPackageID])
+			}
+
+			packageID := pkg.Status.BandwidthPackageID
+			if err := c.Delete(ctx, pkg); err != nil {
+				t.Fatal(err)
+			}
+			if err := reconcileN(ctx, r, pkg, 1); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if got := cloud.called("DeleteCommonBandwidthPackage "+packageID) == 1; got != tt.wantReleased {
+				t.Errorf("released = %v, want %v", got, tt.wantReleased)
+			}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(pkg), pkg); errors.IsNotFound(err) != tt.wantGone {
+				t.Errorf("Get() error = %v, want gone %v", err, tt.wantGone)
+			}
+		})
+	}
+}
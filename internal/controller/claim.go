@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+// claimantFor returns the value written to the claimed-by annotation for a consumer
+func claimantFor(kind, name string) string {
+	return kind + "/" + name
+}
+
+// claimEIP marks a free EIP as claimed by claimant.
+// It returns false when the EIP has been taken by another consumer in the meantime.
+func claimEIP(ctx context.Context, c client.Client, eip *eipv1alpha1.EIP, claimant string) (bool, error) {
+	switch eip.Annotations[eipv1alpha1.AnnotationClaimedBy] {
+	case claimant:
+		return true, nil
+	case "":
+	default:
+		return false, nil
+	}
+
+	if eip.Annotations == nil {
+		eip.Annotations = map[string]string{}
+	}
+	eip.Annotations[eipv1alpha1.AnnotationClaimedBy] = claimant
+
+	// Update relies on the resourceVersion, so a concurrent claim surfaces as a conflict
+	if err := c.Update(ctx, eip); err != nil {
+		if errors.IsConflict(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// unclaimEIP clears the claimed-by annotation if it is still held by claimant
+func unclaimEIP(ctx context.Context, c client.Client, key types.NamespacedName, claimant string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		eip := &eipv1alpha1.EIP{}
+		if err := c.Get(ctx, key, eip); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if eip.Annotations[eipv1alpha1.AnnotationClaimedBy] != claimant {
+			return nil
+		}
+		delete(eip.Annotations, eipv1alpha1.AnnotationClaimedBy)
+		return c.Update(ctx, eip)
+	})
+}
+
+// findClaimedEIP returns the EIP already claimed by claimant, it is used to recover a binding lost from status
+func findClaimedEIP(ctx context.Context, c client.Client, namespace, claimant string) (*eipv1alpha1.EIP, error) {
+	eips := &eipv1alpha1.EIPList{}
+	if err := c.List(ctx, eips, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	for i := range eips.Items {
+		eip := &eips.Items[i]
+		if eip.DeletionTimestamp.IsZero() && eip.Annotations[eipv1alpha1.AnnotationClaimedBy] == claimant {
+			return eip, nil
+		}
+	}
+	return nil, nil
+}
+
+// findAvailableEIPs returns the free and allocated EIPs matching labels, oldest first
+func findAvailableEIPs(ctx context.Context, c client.Client, namespace string, labels map[string]string) ([]*eipv1alpha1.EIP, error) {
+	eips := &eipv1alpha1.EIPList{}
+	if err := c.List(ctx, eips, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+
+	available := make([]*eipv1alpha1.EIP, 0, len(eips.Items))
+	for i := range eips.Items {
+		eip := &eips.Items[i]
+		if eip.DeletionTimestamp.IsZero() && getPoolMemberState(eip) == poolMemberAvailable {
+			available = append(available, eip)
+		}
+	}
+
+	sort.Slice(available, func(i, j int) bool {
+		return available[i].CreationTimestamp.Before(&available[j].CreationTimestamp)
+	})
+	return available, nil
+}
+
+// claimAvailableEIP claims the first free EIP matching labels, it returns nil when none is left
+func claimAvailableEIP(ctx context.Context, c client.Client, namespace string, labels map[string]string, claimant string) (*eipv1alpha1.EIP, error) {
+	candidates, err := findAvailableEIPs(ctx, c, namespace, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, eip := range candidates {
+		ok, err := claimEIP(ctx, c, eip, claimant)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return eip, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+// CloudAccountClients holds the aliyun clients of every CloudAccount with valid credentials
+// and picks the one an EIP is bound to. Clients are added and removed by the CloudAccount controller.
+type CloudAccountClients struct {
+	// Client 读取CloudAccount和命名空间注解
+	Client client.Reader
+	// Default 未绑定CloudAccount的对象使用控制器自身的凭证
+	Default aliyunclient.RegionalAPI
+
+	mu       sync.RWMutex
+	accounts map[string]*accountClients
+}
+
+// accountClients 一个CloudAccount已校验的客户端
+type accountClients struct {
+	fingerprint string
+	clients     aliyunclient.RegionalAPI
+}
+
+// AccountFor returns the CloudAccount an EIP is bound to, "" for the operator's own credentials.
+// Once the EIP exists in the cloud the account it was created or imported with is kept.
+func (c *CloudAccountClients) AccountFor(ctx context.Context, eip *eipv1alpha1.EIP) (string, error) {
+	return c.accountFor(ctx, eip.Namespace, eip.Spec.CloudAccountName, eip.Status.CloudAccountName, eip.Status.AllocationID != "")
+}
+
+// accountFor resolves the CloudAccount of an object from its spec, then from the annotation of its namespace.
+// Cluster scoped objects pass an empty namespace and only use spec. Once the cloud resource exists the account in status is kept.
+func (c *CloudAccountClients) accountFor(ctx context.Context, namespace, specName, statusName string, created bool) (string, error) {
+	if created {
+		return statusName, nil
+	}
+
+	name := specName
+	if name == "" && namespace != "" {
+		ns := &corev1.Namespace{}
+		if err := c.Client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+			return "", err
+		}
+		name = ns.Annotations[eipv1alpha1.AnnotationCloudAccount]
+	}
+	if name == "" {
+		return "", nil
+	}
+
+	account := &eipv1alpha1.CloudAccount{}
+	if err := c.Client.Get(ctx, client.ObjectKey{Name: name}, account); err != nil {
+		if errors.IsNotFound(err) {
+			return "", fmt.Errorf("cloud account %s not found", name)
+		}
+		return "", err
+	}
+	if namespace != "" && !account.AllowsNamespace(namespace) {
+		return "", fmt.Errorf("cloud account %s is not allowed in namespace %s", name, namespace)
+	}
+	return name, nil
+}
+
+// ForAccount returns the clients of a CloudAccount, the default clients for ""
+func (c *CloudAccountClients) ForAccount(name string) (aliyunclient.RegionalAPI, error) {
+	if name == "" {
+		return c.Default, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	account, ok := c.accounts[name]
+	if !ok {
+		return nil, fmt.Errorf("cloud account %s has no valid credentials", name)
+	}
+	return account.clients, nil
+}
+
+// All returns the clients of the default credentials, keyed by "", and of every valid CloudAccount
+func (c *CloudAccountClients) All() map[string]aliyunclient.RegionalAPI {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	all := make(map[string]aliyunclient.RegionalAPI, len(c.accounts)+1)
+	all[""] = c.Default
+	for name, account := range c.accounts {
+		all[name] = account.clients
+	}
+	return all
+}
+
+// cached returns the clients built for a CloudAccount if its credentials have not changed since
+func (c *CloudAccountClients) cached(name, fingerprint string) aliyunclient.RegionalAPI {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if account, ok := c.accounts[name]; ok && account.fingerprint == fingerprint {
+		return account.clients
+	}
+	return nil
+}
+
+// has reports whether a CloudAccount has validated clients
+func (c *CloudAccountClients) has(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.accounts[name]
+	return ok
+}
+
+// set stores the clients of a CloudAccount whose credentials were validated
+func (c *CloudAccountClients) set(name, fingerprint string, clients aliyunclient.RegionalAPI) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.accounts == nil {
+		c.accounts = make(map[string]*accountClients)
+	}
+	c.accounts[name] = &accountClients{fingerprint: fingerprint, clients: clients}
+}
+
+// forget drops the clients of a CloudAccount that was deleted or whose credentials are invalid
+func (c *CloudAccountClients) forget(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.accounts, name)
+}
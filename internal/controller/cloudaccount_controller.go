@@ -0,0 +1,306 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+const (
+	cloudAccountFinalizer = "eip.alibabacloud.com/cloudaccount-finalizer"
+
+	// Reasons
+	reasonCredentialsValid     = "CredentialsValid"
+	reasonInvalidCredentials   = "InvalidCredentials"
+	reasonSecretNotFound       = "SecretNotFound"
+	reasonCloudAccountInUse    = "InUse"
+	reasonCloudAccountNotReady = "CloudAccountNotReady"
+)
+
+const (
+	// eipCloudAccountNameField 按所属的CloudAccount索引EIP
+	eipCloudAccountNameField = ".status.cloudAccountName"
+
+	// cloudAccountRevalidateAfter 定期重新校验凭证，Secret的修改也在此时生效
+	cloudAccountRevalidateAfter = 5 * time.Minute
+)
+
+// CloudAccountReconciler validates the credentials of a CloudAccount and publishes its clients to Accounts
+type CloudAccountReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Record record.EventRecorder
+	// APIReader 直接读取Secret，避免缓存集群内全部Secret
+	APIReader client.Reader
+	Accounts  *CloudAccountClients
+	// Provider 控制器自身的凭证，未引用Secret的CloudAccount以此为基础扮演角色
+	Provider aliyunclient.CredentialProvider
+	// NewClient 按凭证提供者创建指定地域的客户端
+	NewClient func(provider aliyunclient.CredentialProvider, regionID string) (aliyunclient.API, error)
+}
+
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=cloudaccounts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=cloudaccounts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=cloudaccounts/finalizers,verbs=update
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eips,verbs=get;list;watch
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=bandwidthpackages;publicipaddresspools;eipsegments,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop
+func (r *CloudAccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	account := &eipv1alpha1.CloudAccount{}
+	if err := r.Get(ctx, req.NamespacedName, account); err != nil {
+		if errors.IsNotFound(err) {
+			r.Accounts.forget(req.Name)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	eips := &eipv1alpha1.EIPList{}
+	if err := r.List(ctx, eips, client.MatchingFields{eipCloudAccountNameField: account.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	account.Status.EIPCount = int32(len(eips.Items))
+
+	// EIPs in this account can only be released with its credentials, keep it until they are gone
+	if !account.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(account, cloudAccountFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if len(eips.Items) > 0 {
+			r.setCondition(account, conditionTypeReady, metav1.ConditionFalse, reasonCloudAccountInUse,
+				fmt.Sprintf("Cloud account is still used by %d EIPs: %s", len(eips.Items), eipNames(eips.Items)))
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, account)
+		}
+		users, err := r.otherUsers(ctx, account.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(users) > 0 {
+			r.setCondition(account, conditionTypeReady, metav1.ConditionFalse, reasonCloudAccountInUse,
+				fmt.Sprintf("Cloud account is still used by %s", strings.Join(users, ", ")))
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, account)
+		}
+
+		r.Accounts.forget(account.Name)
+		controllerutil.RemoveFinalizer(account, cloudAccountFinalizer)
+		return ctrl.Result{}, r.Update(ctx, account)
+	}
+
+	if !controllerutil.ContainsFinalizer(account, cloudAccountFinalizer) {
+		controllerutil.AddFinalizer(account, cloudAccountFinalizer)
+		if err := r.Update(ctx, account); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// EIP changes only refresh the EIP count, the Secret and the credentials are checked again after cloudAccountRevalidateAfter
+	ready := apimeta.FindStatusCondition(account.Status.Conditions, conditionTypeReady)
+	if validated := account.Status.LastValidatedTime; validated != nil && r.Accounts.has(account.Name) &&
+		ready != nil && ready.ObservedGeneration == account.Generation && time.Since(validated.Time) < cloudAccountRevalidateAfter {
+		return ctrl.Result{RequeueAfter: cloudAccountRevalidateAfter - time.Since(validated.Time)}, r.updateStatus(ctx, account)
+	}
+
+	cred, err := r.credential(ctx, account)
+	if err != nil {
+		r.Accounts.forget(account.Name)
+		r.setCondition(account, conditionTypeReady, metav1.ConditionFalse, reasonSecretNotFound, err.Error())
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, account)
+	}
+
+	fingerprint := cred.Fingerprint()
+	clients := r.Accounts.cached(account.Name, fingerprint)
+	if clients == nil {
+		// All regions share one provider so the role is assumed once per account
+		provider := cred.Provider(r.Provider)
+		clients = aliyunclient.NewClientCache(r.Accounts.Default.DefaultRegion(), func(regionID string) (aliyunclient.API, error) {
+			return r.NewClient(provider, regionID)
+		})
+	}
+
+	identity, err := r.validate(ctx, clients)
+	if err != nil {
+		if isThrottlingError(err) {
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterThrottle}, nil
+		}
+		l.Info("cloud account credentials are invalid", "error", err.Error())
+		if apimeta.IsStatusConditionTrue(account.Status.Conditions, conditionTypeReady) {
+			r.Record.Eventf(account, corev1.EventTypeWarning, reasonInvalidCredentials, "Credentials are invalid: %v", err)
+		}
+		r.Accounts.forget(account.Name)
+		r.setCondition(account, conditionTypeReady, metav1.ConditionFalse, reasonInvalidCredentials, err.Error())
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, account)
+	}
+
+	if !apimeta.IsStatusConditionTrue(account.Status.Conditions, conditionTypeReady) {
+		r.Record.Eventf(account, corev1.EventTypeNormal, reasonCredentialsValid, "Credentials are valid for account %s as %s", identity.AccountID, identity.Arn)
+	}
+	r.Accounts.set(account.Name, fingerprint, clients)
+
+	now := metav1.Now()
+	account.Status.AccountID = identity.AccountID
+	account.Status.Arn = identity.Arn
+	account.Status.IdentityType = identity.IdentityType
+	account.Status.LastValidatedTime = &now
+	r.setCondition(account, conditionTypeReady, metav1.ConditionTrue, reasonCredentialsValid, "Credentials are valid")
+	if err := r.updateStatus(ctx, account); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: cloudAccountRevalidateAfter}, nil
+}
+
+// otherUsers returns the bandwidth packages, public IP address pools and EIP segments managed with a CloudAccount
+func (r *CloudAccountReconciler) otherUsers(ctx context.Context, name string) ([]string, error) {
+	var users []string
+
+	pkgs := &eipv1alpha1.BandwidthPackageList{}
+	if err := r.List(ctx, pkgs); err != nil {
+		return nil, err
+	}
+	for _, pkg := range pkgs.Items {
+		if pkg.Status.CloudAccountName == name && pkg.Status.BandwidthPackageID != "" {
+			users = append(users, "BandwidthPackage "+pkg.Namespace+"/"+pkg.Name)
+		}
+	}
+
+	pools := &eipv1alpha1.PublicIPAddressPoolList{}
+	if err := r.List(ctx, pools); err != nil {
+		return nil, err
+	}
+	for _, pool := range pools.Items {
+		if pool.Status.CloudAccountName == name && pool.Status.PublicIPAddressPoolID != "" {
+			users = append(users, "PublicIPAddressPool "+pool.Name)
+		}
+	}
+
+	segments := &eipv1alpha1.EIPSegmentList{}
+	if err := r.List(ctx, segments); err != nil {
+		return nil, err
+	}
+	for _, segment := range segments.Items {
+		if segment.Status.CloudAccountName == name && segment.Status.SegmentInstanceID != "" {
+			users = append(users, "EIPSegment "+segment.Namespace+"/"+segment.Name)
+		}
+	}
+
+	return users, nil
+}
+
+// credential builds the credential of a CloudAccount, without a Secret the operator's own credentials assume the role
+func (r *CloudAccountReconciler) credential(ctx context.Context, account *eipv1alpha1.CloudAccount) (aliyunclient.Credential, error) {
+	cred := aliyunclient.Credential{
+		RoleARN:         account.Spec.RoleARN,
+		RoleSessionName: account.Spec.RoleSessionName,
+	}
+
+	ref := account.Spec.SecretRef
+	if ref == nil {
+		return cred, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		return cred, fmt.Errorf("failed to get secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	cred.AccessKeyID = string(secret.Data[eipv1alpha1.CloudAccountSecretKeyAccessKeyID])
+	cred.AccessKeySecret = string(secret.Data[eipv1alpha1.CloudAccountSecretKeyAccessKeySecret])
+	if cred.AccessKeyID == "" || cred.AccessKeySecret == "" {
+		return cred, fmt.Errorf("secret %s/%s must contain %s and %s", ref.Namespace, ref.Name,
+			eipv1alpha1.CloudAccountSecretKeyAccessKeyID, eipv1alpha1.CloudAccountSecretKeyAccessKeySecret)
+	}
+	return cred, nil
+}
+
+// validate checks the credentials by asking STS who they belong to
+func (r *CloudAccountReconciler) validate(ctx context.Context, clients aliyunclient.RegionalAPI) (*aliyunclient.CallerIdentity, error) {
+	api, err := clients.ForRegion("")
+	if err != nil {
+		return nil, err
+	}
+	return api.GetCallerIdentity(ctx)
+}
+
+// setCondition sets a condition on the CloudAccount
+func (r *CloudAccountReconciler) setCondition(account *eipv1alpha1.CloudAccount, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: account.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	apimeta.SetStatusCondition(&account.Status.Conditions, condition)
+}
+
+// updateStatus updates the CloudAccount status
+func (r *CloudAccountReconciler) updateStatus(ctx context.Context, account *eipv1alpha1.CloudAccount) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return r.Status().Update(ctx, account)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CloudAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &eipv1alpha1.EIP{}, eipCloudAccountNameField,
+		func(obj client.Object) []string {
+			return []string{obj.(*eipv1alpha1.EIP).Status.CloudAccountName}
+		}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&eipv1alpha1.CloudAccount{}).
+		Watches(&eipv1alpha1.EIP{}, handler.EnqueueRequestsFromMapFunc(cloudAccountForEIP)).
+		Complete(r)
+}
+
+// cloudAccountForEIP maps an EIP to the CloudAccount it belongs to
+func cloudAccountForEIP(ctx context.Context, obj client.Object) []reconcile.Request {
+	name := obj.(*eipv1alpha1.EIP).Status.CloudAccountName
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+func TestAccountFor(t *testing.T) {
+	teamA := &eipv1alpha1.CloudAccount{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}
+	teamB := &eipv1alpha1.CloudAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-b"},
+		Spec:       eipv1alpha1.CloudAccountSpec{AllowedNamespaces: []string{"team-b"}},
+	}
+	annotated := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "annotated",
+		Annotations: map[string]string{eipv1alpha1.AnnotationCloudAccount: "team-a"},
+	}}
+	accounts := accountsFor(newFakeClient(teamA, teamB, testNamespace(), annotated), newFakeCloud())
+
+	tests := []struct {
+		name       string
+		namespace  string
+		specName   string
+		statusName string
+		created    bool
+		want       string
+		wantErr    bool
+	}{
+		{name: "default credentials", namespace: "default"},
+		{name: "from spec", namespace: "default", specName: "team-a", want: "team-a"},
+		{name: "from namespace annotation", namespace: "annotated", want: "team-a"},
+		{name: "spec wins over the annotation", namespace: "annotated", specName: "team-b", wantErr: true},
+		{name: "namespace not allowed", namespace: "default", specName: "team-b", wantErr: true},
+		{name: "allowed namespace", namespace: "team-b", specName: "team-b", want: "team-b"},
+		{name: "missing account", namespace: "default", specName: "team-c", wantErr: true},
+		{name: "cluster scoped object", specName: "team-b", want: "team-b"},
+		{name: "created resource keeps its account", namespace: "default", specName: "team-a", statusName: "team-c", created: true, want: "team-c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := accounts.accountFor(context.Background(), tt.namespace, tt.specName, tt.statusName, tt.created)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("accountFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("accountFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForAccount(t *testing.T) {
+	accounts := accountsFor(newFakeClient(), newFakeCloud())
+	teamA := accountsFor(newFakeClient(), newFakeCloud()).Default
+	accounts.set("team-a", "fingerprint", teamA)
+
+	if got, err := accounts.ForAccount(""); err != nil || got != accounts.Default {
+		t.Errorf("ForAccount(\"\") = %v, %v, want the default clients", got, err)
+	}
+	if got, err := accounts.ForAccount("team-a"); err != nil || got != teamA {
+		t.Errorf("ForAccount(team-a) = %v, %v, want the account clients", got, err)
+	}
+	if _, err := accounts.ForAccount("team-b"); err == nil {
+		t.Errorf("ForAccount(team-b) error = nil, want an error for an account without valid credentials")
+	}
+
+	accounts.forget("team-a")
+	if _, err := accounts.ForAccount("team-a"); err == nil {
+		t.Errorf("ForAccount(team-a) error = nil after forget")
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+func TestDesiredAttributes(t *testing.T) {
+	synced := eipv1alpha1.EIPStatus{Bandwidth: "10", Name: "web", Description: "web eip"}
+
+	tests := []struct {
+		name   string
+		spec   eipv1alpha1.EIPSpec
+		status eipv1alpha1.EIPStatus
+		want   aliyunclient.EIPAttributes
+	}{
+		{
+			name:   "in sync",
+			spec:   eipv1alpha1.EIPSpec{Bandwidth: "10", Name: "web", Description: "web eip"},
+			status: synced,
+		},
+		{
+			name:   "unset fields are left alone",
+			status: synced,
+		},
+		{
+			name:   "all attributes drifted",
+			spec:   eipv1alpha1.EIPSpec{Bandwidth: "20", Name: "api", Description: "api eip"},
+			status: synced,
+			want:   aliyunclient.EIPAttributes{Bandwidth: "20", Name: "api", Description: "api eip"},
+		},
+		{
+			name: "bandwidth follows the bandwidth package",
+			spec: eipv1alpha1.EIPSpec{Bandwidth: "20", Name: "api"},
+			status: eipv1alpha1.EIPStatus{
+				Bandwidth: "10", Name: "web", BandwidthPackageID: "cbwp-123",
+			},
+			want: aliyunclient.EIPAttributes{Name: "api"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eip := &eipv1alpha1.EIP{Spec: tt.spec, Status: tt.status}
+			if got := desiredAttributes(eip); *got != tt.want {
+				t.Errorf("desiredAttributes() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDriftedImmutableFields(t *testing.T) {
+	observed := eipv1alpha1.EIPStatus{
+		InternetChargeType: "PayByTraffic",
+		InstanceChargeType: "PostPaid",
+		ISP:                "BGP",
+		Bandwidth:          "10",
+		Name:               "web",
+	}
+
+	tests := []struct {
+		name   string
+		spec   eipv1alpha1.EIPSpec
+		status eipv1alpha1.EIPStatus
+		want   []string
+	}{
+		{
+			name:   "in sync",
+			spec:   eipv1alpha1.EIPSpec{InternetChargeType: "PayByTraffic", InstanceChargeType: "PostPaid", ISP: "BGP"},
+			status: observed,
+		},
+		{
+			name:   "in place fields are not reported",
+			spec:   eipv1alpha1.EIPSpec{Bandwidth: "20", Name: "api", ISP: "BGP"},
+			status: observed,
+		},
+		{
+			name:   "unset spec is not drift",
+			status: observed,
+		},
+		{
+			name:   "unobserved status is not drift",
+			spec:   eipv1alpha1.EIPSpec{ISP: "BGP_PRO"},
+			status: eipv1alpha1.EIPStatus{},
+		},
+		{
+			name:   "replacement fields drifted",
+			spec:   eipv1alpha1.EIPSpec{InternetChargeType: "PayByBandwidth", ISP: "BGP_PRO", ResourceGroupID: "rg-123"},
+			status: observed,
+			want: []string{
+				"internetChargeType: spec PayByBandwidth, cloud PayByTraffic",
+				"isp: spec BGP_PRO, cloud BGP",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eip := &eipv1alpha1.EIP{Spec: tt.spec, Status: tt.status}
+			if got := driftedImmutableFields(eip); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("driftedImmutableFields() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
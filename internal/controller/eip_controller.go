@@ -18,23 +18,30 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
 	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+	"github.com/chrisliu1995/alibabacloud-eip-operator/pkg/limits"
 )
 
 const (
@@ -44,21 +51,45 @@ const (
 	conditionTypeReady       = "Ready"
 	conditionTypeSynced      = "Synced"
 	conditionTypeProgressing = "Progressing"
+	conditionTypeSpecDrift   = "SpecDrift"
+	conditionTypeConflict    = "Conflict"
+	// conditionTypeOwnershipConflict 云上EIP归属其他集群
+	conditionTypeOwnershipConflict = "OwnershipConflict"
 
 	// Reasons
-	reasonCreating   = "Creating"
-	reasonCreated    = "Created"
-	reasonUpdating   = "Updating"
-	reasonUpdated    = "Updated"
-	reasonDeleting   = "Deleting"
-	reasonDeleted    = "Deleted"
-	reasonSyncFailed = "SyncFailed"
-	reasonThrottled  = "Throttled"
+	reasonCreating    = "Creating"
+	reasonCreated     = "Created"
+	reasonUpdating    = "Updating"
+	reasonUpdated     = "Updated"
+	reasonDeleting    = "Deleting"
+	reasonDeleted     = "Deleted"
+	reasonSyncFailed  = "SyncFailed"
+	reasonThrottled   = "Throttled"
+	reasonInvalidSpec = "InvalidSpec"
+
+	reasonBandwidthPackageNotReady = "BandwidthPackageNotReady"
+	reasonImmutableFieldChanged    = "ImmutableFieldChanged"
+	reasonInSync                   = "InSync"
+	reasonDeletionBlocked          = "DeletionBlocked"
+	reasonDisassociating           = "Disassociating"
+	reasonImportNotFound           = "ImportNotFound"
+	reasonImportAmbiguous          = "ImportAmbiguous"
+	reasonImported                 = "Imported"
+	reasonDuplicateAllocationID    = "DuplicateAllocationID"
+	reasonNoConflict               = "NoConflict"
+	reasonOwnedByAnotherCluster    = "OwnedByAnotherCluster"
+	reasonOwnershipForced          = "OwnershipForced"
+)
+
+const (
+	// eipBandwidthPackageNameField 按引用的BandwidthPackage名称索引EIP
+	eipBandwidthPackageNameField = ".spec.bandwidthPackageName"
 )
 
 const (
 	eipCtrlRequeueAfter         = 30 * time.Second
-	eipCtrlRequeueAfterThrottle = 2 * time.Minute // 流控时使用更长的重试间隔
+	eipCtrlRequeueAfterThrottle = 2 * time.Minute  // 流控时使用更长的重试间隔
+	eipCtrlRequeueAfterDeletion = 10 * time.Second // 删除等待EIP解绑时的重试间隔
 )
 
 // isThrottlingError 检查是否为流控错误
@@ -88,12 +119,42 @@ type EIPReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Record record.EventRecorder
-	Aliyun aliyunclient.API
+	// Accounts 按EIP绑定的CloudAccount选择阿里云客户端
+	Accounts *CloudAccountClients
+	// BandwidthLimits 带宽限制表，按EIP所在地域取生效的规则，未设置时使用内置限制表
+	BandwidthLimits *limits.Table
+	// ClusterID 写入归属标签的集群标识，属于其他集群的EIP不会被导入或释放
+	ClusterID string
+}
+
+// bandwidthLimits returns the limits in effect for a region, from the configured table or the built-in one
+func (r *EIPReconciler) bandwidthLimits(regionID string) *limits.Table {
+	if r.BandwidthLimits == nil {
+		return limits.Default().ForRegion(regionID)
+	}
+	return r.BandwidthLimits.ForRegion(regionID)
+}
+
+// eipRegion returns the region of an EIP: spec.regionID, then the region it was synced in, then the default region
+func eipRegion(eip *eipv1alpha1.EIP, clients aliyunclient.RegionalAPI) string {
+	return resourceRegion(eip.Spec.RegionID, eip.Status.RegionID, clients)
+}
+
+// resourceRegion returns the region of a cloud resource: the one in spec, then the one recorded in status, then the default region
+func resourceRegion(specRegionID, statusRegionID string, clients aliyunclient.RegionalAPI) string {
+	if specRegionID != "" {
+		return specRegionID
+	}
+	if statusRegionID != "" {
+		return statusRegionID
+	}
+	return clients.DefaultRegion()
 }
 
 //+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eips,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eips/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eips/finalizers,verbs=update
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=bandwidthpackages,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop
@@ -112,18 +173,43 @@ func (r *EIPReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, err
 	}
 
+	var clients aliyunclient.RegionalAPI
+	account, err := r.Accounts.AccountFor(ctx, eip)
+	if err == nil {
+		eip.Status.CloudAccountName = account
+		clients, err = r.Accounts.ForAccount(account)
+	}
+	if err != nil {
+		// An EIP that was never created has nothing to release, otherwise deletion also waits for the account
+		if !eip.DeletionTimestamp.IsZero() && eip.Status.AllocationID == "" {
+			controllerutil.RemoveFinalizer(eip, eipFinalizer)
+			return ctrl.Result{}, r.Update(ctx, eip)
+		}
+		l.Info("cloud account is not ready", "cloudAccount", account, "error", err.Error())
+		r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonCloudAccountNotReady, err.Error())
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, eip)
+	}
+	eip.Status.RegionID = eipRegion(eip, clients)
+	api, err := clients.ForRegion(eip.Status.RegionID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Check if the EIP instance is marked to be deleted
 	if !eip.ObjectMeta.DeletionTimestamp.IsZero() {
 		if controllerutil.ContainsFinalizer(eip, eipFinalizer) {
 			// Run finalization logic
-			if err := r.finalizeEIP(ctx, eip); err != nil {
+			done, err := r.finalizeEIP(ctx, api, eip)
+			if err != nil {
 				return ctrl.Result{}, err
 			}
+			if !done {
+				return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterDeletion}, nil
+			}
 
 			// Remove finalizer
 			controllerutil.RemoveFinalizer(eip, eipFinalizer)
-			err := r.Update(ctx, eip)
-			if err != nil {
+			if err := r.Update(ctx, eip); err != nil {
 				return ctrl.Result{}, err
 			}
 		}
@@ -140,7 +226,7 @@ func (r *EIPReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	}
 
 	// Reconcile EIP
-	result, err := r.reconcileEIP(ctx, eip)
+	result, err := r.reconcileEIP(ctx, api, eip)
 	if err != nil {
 		l.Error(err, "failed to reconcile EIP")
 		r.Record.Eventf(eip, "Warning", "ReconcileFailed", "Failed to reconcile EIP: %v", err)
@@ -151,7 +237,7 @@ func (r *EIPReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 }
 
 // reconcileEIP handles the main reconciliation logic
-func (r *EIPReconciler) reconcileEIP(ctx context.Context, eip *eipv1alpha1.EIP) (ctrl.Result, error) {
+func (r *EIPReconciler) reconcileEIP(ctx context.Context, api aliyunclient.API, eip *eipv1alpha1.EIP) (ctrl.Result, error) {
 	l := log.FromContext(ctx)
 
 	// If AllocationID is not set, create a new EIP
@@ -162,6 +248,11 @@ func (r *EIPReconciler) reconcileEIP(ctx context.Context, eip *eipv1alpha1.EIP)
 			if err := r.Update(ctx, eip); err != nil {
 				return ctrl.Result{}, err
 			}
+		} else if eip.Spec.ImportFrom != nil {
+			// Adopt the single existing EIP matching spec.importFrom
+			if result, err := r.importEIP(ctx, api, eip); err != nil || !result.IsZero() {
+				return result, err
+			}
 		} else {
 			// Create new EIP
 			l.Info("creating new EIP")
@@ -170,7 +261,7 @@ func (r *EIPReconciler) reconcileEIP(ctx context.Context, eip *eipv1alpha1.EIP)
 				return ctrl.Result{}, err
 			}
 
-			allocationID, err := r.createEIP(ctx, eip)
+			allocationID, err := r.createEIP(ctx, api, eip)
 			if err != nil {
 				// 检查是否为流控错误
 				if isThrottlingError(err) {
@@ -185,8 +276,13 @@ func (r *EIPReconciler) reconcileEIP(ctx context.Context, eip *eipv1alpha1.EIP)
 				return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
 			}
 
-			eip.Spec.AllocationID = allocationID
+			// Persist the ID in status first, the ownership tag still recovers it if both writes are lost
 			eip.Status.AllocationID = allocationID
+			eip.Status.Provenance = eipv1alpha1.EIPProvenanceCreated
+			if err := r.updateStatus(ctx, eip); err != nil {
+				return ctrl.Result{}, err
+			}
+			eip.Spec.AllocationID = allocationID
 			if err := r.Update(ctx, eip); err != nil {
 				return ctrl.Result{}, err
 			}
@@ -196,8 +292,30 @@ func (r *EIPReconciler) reconcileEIP(ctx context.Context, eip *eipv1alpha1.EIP)
 		}
 	}
 
+	// Only the oldest object referencing a cloud EIP may change it
+	others, err := allocationIDConflicts(ctx, r.Client, eip, eip.Spec.AllocationID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(others) > 0 {
+		r.setCondition(eip, conditionTypeConflict, metav1.ConditionTrue, reasonDuplicateAllocationID,
+			fmt.Sprintf("EIP %s is also referenced by %s", eip.Spec.AllocationID, eipNames(others)))
+		r.Record.Eventf(eip, "Warning", reasonDuplicateAllocationID, "EIP %s is also referenced by %s", eip.Spec.AllocationID, eipNames(others))
+		if !holdsAllocationID(eip, others) {
+			l.Info("EIP is managed by an older object, not reconciling", "allocationID", eip.Spec.AllocationID, "others", eipNames(others))
+			r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonDuplicateAllocationID,
+				fmt.Sprintf("EIP %s is managed by an older object, remove the duplicate reference", eip.Spec.AllocationID))
+			if err := r.updateStatus(ctx, eip); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, nil
+		}
+	} else {
+		r.setCondition(eip, conditionTypeConflict, metav1.ConditionFalse, reasonNoConflict, "No other EIP object references this EIP")
+	}
+
 	// Sync EIP status from Aliyun
-	if err := r.syncEIPStatus(ctx, eip); err != nil {
+	if err := r.syncEIPStatus(ctx, api, eip); err != nil {
 		// 检查是否为流控错误
 		if isThrottlingError(err) {
 			l.Info("API throttled during status sync, will retry later")
@@ -209,60 +327,142 @@ func (r *EIPReconciler) reconcileEIP(ctx context.Context, eip *eipv1alpha1.EIP)
 		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
 	}
 
-	// Update bandwidth if needed
-	if eip.Spec.Bandwidth != "" && eip.Status.Bandwidth != eip.Spec.Bandwidth && eip.Status.BandwidthPackageID == "" {
-		l.Info("updating EIP bandwidth", "from", eip.Status.Bandwidth, "to", eip.Spec.Bandwidth)
-		r.setCondition(eip, conditionTypeProgressing, metav1.ConditionTrue, reasonUpdating, "Updating EIP bandwidth")
+	// Leave EIPs owned by another cluster alone unless forced
+	if owner, foreign := foreignCluster(eip.Status.Tags, r.ClusterID); foreign {
+		if !forceOwnership(eip) {
+			l.Info("EIP is owned by another cluster, not adopting", "allocationID", eip.Spec.AllocationID, "cluster", owner)
+			r.Record.Eventf(eip, "Warning", reasonOwnedByAnotherCluster, "EIP %s is owned by cluster %s", eip.Spec.AllocationID, owner)
+			r.setCondition(eip, conditionTypeOwnershipConflict, metav1.ConditionTrue, reasonOwnedByAnotherCluster,
+				fmt.Sprintf("EIP %s is owned by cluster %s, set annotation %s=true to take it over", eip.Spec.AllocationID, owner, eipv1alpha1.AnnotationForceOwnership))
+			r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonOwnedByAnotherCluster,
+				fmt.Sprintf("EIP %s is owned by cluster %s", eip.Spec.AllocationID, owner))
+			if err := r.updateStatus(ctx, eip); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, nil
+		}
+		l.Info("taking over EIP owned by another cluster", "allocationID", eip.Spec.AllocationID, "cluster", owner)
+		r.Record.Eventf(eip, "Warning", reasonOwnershipForced, "Taking over EIP %s from cluster %s", eip.Spec.AllocationID, owner)
+		r.setCondition(eip, conditionTypeOwnershipConflict, metav1.ConditionTrue, reasonOwnershipForced,
+			fmt.Sprintf("EIP %s was owned by cluster %s, taken over by annotation %s", eip.Spec.AllocationID, owner, eipv1alpha1.AnnotationForceOwnership))
+	} else {
+		r.setCondition(eip, conditionTypeOwnershipConflict, metav1.ConditionFalse, reasonNoConflict, "EIP is owned by this cluster")
+	}
+
+	// Update mutable attributes if needed
+	attrs := desiredAttributes(eip)
+	var bandwidthViolation *limits.Violation
+	if attrs.Bandwidth != "" {
+		// Check against the cloud side ISP and charge types, the spec may have drifted from them
+		bandwidthViolation = r.bandwidthLimits(eip.Status.RegionID).Check(eip.Status.ISP, eip.Status.InternetChargeType, eip.Status.InstanceChargeType, attrs.Bandwidth)
+		if bandwidthViolation != nil {
+			l.Info("bandwidth rejected by limits table", "bandwidth", attrs.Bandwidth, "reason", bandwidthViolation.Message)
+			r.Record.Eventf(eip, "Warning", reasonInvalidSpec, "Bandwidth not applied: %v", bandwidthViolation)
+			attrs.Bandwidth = ""
+		}
+	}
+	if !attrs.IsEmpty() {
+		l.Info("updating EIP attributes", "bandwidth", attrs.Bandwidth, "name", attrs.Name, "description", attrs.Description)
+		r.setCondition(eip, conditionTypeProgressing, metav1.ConditionTrue, reasonUpdating, "Updating EIP attributes")
 		if err := r.updateStatus(ctx, eip); err != nil {
 			return ctrl.Result{}, err
 		}
 
-		if err := r.Aliyun.ModifyEipAddressAttribute(ctx, eip.Spec.AllocationID, eip.Spec.Bandwidth); err != nil {
-			r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonSyncFailed, fmt.Sprintf("Failed to update bandwidth: %v", err))
+		if err := api.ModifyEipAddressAttribute(ctx, eip.Spec.AllocationID, attrs); err != nil {
+			r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonSyncFailed, fmt.Sprintf("Failed to update attributes: %v", err))
+			_ = r.updateStatus(ctx, eip)
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
+		}
+
+		r.Record.Eventf(eip, "Normal", "Updated", "Updated EIP attributes: %s", attrs)
+		r.setCondition(eip, conditionTypeProgressing, metav1.ConditionFalse, reasonUpdated, "EIP attributes updated")
+	}
+
+	// Keep cloud deletion protection at the desired value
+	if want := eip.Spec.DeletionProtection; want != nil && *want != eip.Status.DeletionProtection {
+		l.Info("setting deletion protection", "enable", *want)
+		if err := api.DeletionProtection(ctx, aliyunclient.DeletionProtectionTypeEIP, eip.Spec.AllocationID, *want); err != nil {
+			if isThrottlingError(err) {
+				r.Record.Eventf(eip, "Warning", "Throttled", "API request throttled, will retry in %v", eipCtrlRequeueAfterThrottle)
+				return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterThrottle}, nil
+			}
+			r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonSyncFailed, fmt.Sprintf("Failed to set deletion protection: %v", err))
 			_ = r.updateStatus(ctx, eip)
 			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
 		}
+		eip.Status.DeletionProtection = *want
+		r.Record.Eventf(eip, "Normal", "Updated", "Set deletion protection to %t", *want)
+	}
 
-		r.Record.Eventf(eip, "Normal", "Updated", "Updated EIP bandwidth to %s", eip.Spec.Bandwidth)
-		r.setCondition(eip, conditionTypeProgressing, metav1.ConditionFalse, reasonUpdated, "EIP bandwidth updated")
+	// Fields that cannot be changed in place are only reported
+	if drifted := driftedImmutableFields(eip); len(drifted) > 0 {
+		r.setCondition(eip, conditionTypeSpecDrift, metav1.ConditionTrue, reasonImmutableFieldChanged,
+			fmt.Sprintf("Fields cannot be changed in place, recreate the EIP to apply them: %s", strings.Join(drifted, "; ")))
+	} else {
+		r.setCondition(eip, conditionTypeSpecDrift, metav1.ConditionFalse, reasonInSync, "All fields match the cloud EIP")
 	}
 
 	// Handle bandwidth package
-	if eip.Spec.BandwidthPackageID != "" {
-		if eip.Status.BandwidthPackageID != eip.Spec.BandwidthPackageID {
+	packageID, ok, err := r.resolveBandwidthPackageID(ctx, eip)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !ok {
+		// The BandwidthPackage watch requeues the EIP once the package is created
+		r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonBandwidthPackageNotReady,
+			fmt.Sprintf("BandwidthPackage %s is not ready", eip.Spec.BandwidthPackageName))
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, eip)
+	}
+	if packageID != "" {
+		if eip.Status.BandwidthPackageID != packageID {
 			// Remove from old package if exists
 			if eip.Status.BandwidthPackageID != "" {
 				l.Info("removing EIP from bandwidth package", "packageID", eip.Status.BandwidthPackageID)
-				if err := r.Aliyun.RemoveCommonBandwidthPackageIP(ctx, eip.Spec.AllocationID, eip.Status.BandwidthPackageID); err != nil {
+				if err := api.RemoveCommonBandwidthPackageIP(ctx, eip.Spec.AllocationID, eip.Status.BandwidthPackageID); err != nil {
 					l.Error(err, "failed to remove EIP from bandwidth package")
 				}
 			}
 
 			// Add to new package
-			l.Info("adding EIP to bandwidth package", "packageID", eip.Spec.BandwidthPackageID)
-			if err := r.Aliyun.AddCommonBandwidthPackageIP(ctx, eip.Spec.AllocationID, eip.Spec.BandwidthPackageID); err != nil {
+			l.Info("adding EIP to bandwidth package", "packageID", packageID)
+			if err := api.AddCommonBandwidthPackageIP(ctx, eip.Spec.AllocationID, packageID); err != nil {
 				r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonSyncFailed, fmt.Sprintf("Failed to add to bandwidth package: %v", err))
 				_ = r.updateStatus(ctx, eip)
 				return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
 			}
 
-			r.Record.Eventf(eip, "Normal", "Updated", "Added EIP to bandwidth package: %s", eip.Spec.BandwidthPackageID)
+			r.Record.Eventf(eip, "Normal", "Updated", "Added EIP to bandwidth package: %s", packageID)
 		}
 	} else if eip.Status.BandwidthPackageID != "" {
 		// Remove from bandwidth package
 		l.Info("removing EIP from bandwidth package", "packageID", eip.Status.BandwidthPackageID)
-		if err := r.Aliyun.RemoveCommonBandwidthPackageIP(ctx, eip.Spec.AllocationID, eip.Status.BandwidthPackageID); err != nil {
+		if err := api.RemoveCommonBandwidthPackageIP(ctx, eip.Spec.AllocationID, eip.Status.BandwidthPackageID); err != nil {
 			l.Error(err, "failed to remove EIP from bandwidth package")
 		}
 	}
 
+	// Converge cloud tags to spec.tags
+	if err := r.reconcileTags(ctx, api, eip); err != nil {
+		if isThrottlingError(err) {
+			r.Record.Eventf(eip, "Warning", "Throttled", "API request throttled during tag sync, will retry in %v", eipCtrlRequeueAfterThrottle)
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterThrottle}, nil
+		}
+		r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonSyncFailed, fmt.Sprintf("Failed to sync tags: %v", err))
+		_ = r.updateStatus(ctx, eip)
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
+	}
+
 	// Re-sync status
-	if err := r.syncEIPStatus(ctx, eip); err != nil {
+	if err := r.syncEIPStatus(ctx, api, eip); err != nil {
 		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
 	}
 
 	// Set Ready condition
-	r.setCondition(eip, conditionTypeReady, metav1.ConditionTrue, "Available", "EIP is ready")
+	if bandwidthViolation != nil {
+		r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonInvalidSpec, fmt.Sprintf("Bandwidth not applied: %v", bandwidthViolation))
+	} else {
+		r.setCondition(eip, conditionTypeReady, metav1.ConditionTrue, "Available", "EIP is ready")
+	}
 	r.setCondition(eip, conditionTypeSynced, metav1.ConditionTrue, "Synced", "EIP synced successfully")
 	if err := r.updateStatus(ctx, eip); err != nil {
 		return ctrl.Result{}, err
@@ -271,30 +471,186 @@ func (r *EIPReconciler) reconcileEIP(ctx context.Context, eip *eipv1alpha1.EIP)
 	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
 }
 
-// createEIP creates a new EIP instance
-func (r *EIPReconciler) createEIP(ctx context.Context, eip *eipv1alpha1.EIP) (string, error) {
+// desiredAttributes returns the attributes that differ between spec and the synced status
+func desiredAttributes(eip *eipv1alpha1.EIP) *aliyunclient.EIPAttributes {
+	attrs := &aliyunclient.EIPAttributes{}
+	// Bandwidth of an EIP in a bandwidth package follows the package
+	if eip.Spec.Bandwidth != "" && eip.Status.Bandwidth != eip.Spec.Bandwidth && eip.Status.BandwidthPackageID == "" {
+		attrs.Bandwidth = eip.Spec.Bandwidth
+	}
+	if eip.Spec.Name != "" && eip.Status.Name != eip.Spec.Name {
+		attrs.Name = eip.Spec.Name
+	}
+	if eip.Spec.Description != "" && eip.Status.Description != eip.Spec.Description {
+		attrs.Description = eip.Spec.Description
+	}
+	return attrs
+}
+
+// driftedImmutableFields lists the fields whose spec differs from the cloud EIP but cannot be modified in place
+func driftedImmutableFields(eip *eipv1alpha1.EIP) []string {
+	var drifted []string
+	for _, f := range eipv1alpha1.EIPSpecFields {
+		if f.Mutability == eipv1alpha1.FieldMutableInPlace || f.Observed == nil {
+			continue
+		}
+		desired, observed := fmt.Sprint(f.Get(&eip.Spec)), f.Observed(&eip.Status)
+		if desired != "" && observed != "" && desired != observed {
+			drifted = append(drifted, fmt.Sprintf("%s: spec %s, cloud %s", f.Name, desired, observed))
+		}
+	}
+	return drifted
+}
+
+// resolveBandwidthPackageID returns the bandwidth package the EIP should join.
+// It returns false while the referenced BandwidthPackage has not been created yet.
+func (r *EIPReconciler) resolveBandwidthPackageID(ctx context.Context, eip *eipv1alpha1.EIP) (string, bool, error) {
+	if eip.Spec.BandwidthPackageName == "" {
+		return eip.Spec.BandwidthPackageID, true, nil
+	}
+
+	pkg := &eipv1alpha1.BandwidthPackage{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: eip.Namespace, Name: eip.Spec.BandwidthPackageName}, pkg)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	if !pkg.DeletionTimestamp.IsZero() || pkg.Status.BandwidthPackageID == "" {
+		return "", false, nil
+	}
+	return pkg.Status.BandwidthPackageID, true, nil
+}
+
+// importEIP resolves spec.importFrom and pins the matched EIP in status.
+// Nothing is adopted unless exactly one EIP matches, a non-zero result means the import is still pending.
+func (r *EIPReconciler) importEIP(ctx context.Context, api aliyunclient.API, eip *eipv1alpha1.EIP) (ctrl.Result, error) {
 	l := log.FromContext(ctx)
+	source := eip.Spec.ImportFrom
 
-	opts := &aliyunclient.EIPOptions{
-		InternetChargeType:      eip.Spec.InternetChargeType,
-		Bandwidth:               eip.Spec.Bandwidth,
-		ISP:                     eip.Spec.ISP,
-		InstanceChargeType:      eip.Spec.InstanceChargeType,
-		PublicIPAddressPoolID:   eip.Spec.PublicIPAddressPoolID,
-		ResourceGroupID:         eip.Spec.ResourceGroupID,
-		Name:                    eip.Spec.Name,
-		Description:             eip.Spec.Description,
-		SecurityProtectionTypes: eip.Spec.SecurityProtectionTypes,
+	var candidates []aliyunclient.EIPAddress
+	var err error
+	if source.IPAddress != "" {
+		candidates, err = api.DescribeEipAddresses(ctx, "", source.IPAddress, "", "")
+	} else {
+		candidates, err = api.DescribeEipAddressesByTags(ctx, source.TagSelector)
+	}
+	if err != nil {
+		if isThrottlingError(err) {
+			r.Record.Eventf(eip, "Warning", "Throttled", "API request throttled, will retry in %v", eipCtrlRequeueAfterThrottle)
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterThrottle}, nil
+		}
+		r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonSyncFailed, fmt.Sprintf("Failed to look up EIP to import: %v", err))
+		_ = r.updateStatus(ctx, eip)
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
+	}
+
+	switch len(candidates) {
+	case 0:
+		l.Info("no EIP matches importFrom", "ipAddress", source.IPAddress, "tagSelector", source.TagSelector)
+		r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonImportNotFound, "No EIP matches spec.importFrom")
+		if err := r.updateStatus(ctx, eip); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, nil
+	case 1:
+	default:
+		ids := make([]string, 0, len(candidates))
+		for _, c := range candidates {
+			ids = append(ids, c.AllocationID)
+		}
+		l.Info("importFrom matches multiple EIPs, refusing to adopt", "allocationIDs", ids)
+		r.Record.Eventf(eip, "Warning", reasonImportAmbiguous, "spec.importFrom matches %d EIPs: %s", len(ids), strings.Join(ids, ", "))
+		r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonImportAmbiguous,
+			fmt.Sprintf("spec.importFrom matches %d EIPs (%s), narrow the selector", len(ids), strings.Join(ids, ", ")))
+		if err := r.updateStatus(ctx, eip); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, nil
+	}
+
+	allocationID := candidates[0].AllocationID
+	others, err := allocationIDConflicts(ctx, r.Client, eip, allocationID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(others) > 0 {
+		l.Info("matched EIP is already managed, refusing to adopt", "allocationID", allocationID, "others", eipNames(others))
+		r.Record.Eventf(eip, "Warning", reasonDuplicateAllocationID, "Matched EIP %s is already managed by %s", allocationID, eipNames(others))
+		r.setCondition(eip, conditionTypeReady, metav1.ConditionFalse, reasonDuplicateAllocationID,
+			fmt.Sprintf("Matched EIP %s is already managed by %s", allocationID, eipNames(others)))
+		if err := r.updateStatus(ctx, eip); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, nil
+	}
+
+	l.Info("importing EIP", "allocationID", allocationID)
+
+	// Pin the resolved ID in status first, later reconciles never resolve the selector again
+	eip.Status.AllocationID = allocationID
+	eip.Status.Provenance = eipv1alpha1.EIPProvenanceImported
+	if err := r.updateStatus(ctx, eip); err != nil {
+		return ctrl.Result{}, err
+	}
+	eip.Spec.AllocationID = allocationID
+	if err := r.Update(ctx, eip); err != nil {
+		return ctrl.Result{}, err
 	}
 
-	if opts.InternetChargeType == "" {
-		opts.InternetChargeType = "PayByTraffic"
+	r.Record.Eventf(eip, "Normal", reasonImported, "Imported EIP %s (%s)", allocationID, candidates[0].IPAddress)
+	return ctrl.Result{}, nil
+}
+
+// forceOwnership reports whether the object may adopt or release an EIP owned by another cluster
+func forceOwnership(eip *eipv1alpha1.EIP) bool {
+	return eip.Annotations[eipv1alpha1.AnnotationForceOwnership] == "true"
+}
+
+// createEIP creates a new EIP instance, or recovers the one a previous reconcile allocated for this object
+func (r *EIPReconciler) createEIP(ctx context.Context, api aliyunclient.API, eip *eipv1alpha1.EIP) (string, error) {
+	l := log.FromContext(ctx)
+
+	// A previous reconcile may have allocated the EIP and failed before persisting its ID
+	owned, err := api.DescribeEipAddressesByTags(ctx, ownerUIDSelector(eip))
+	if err != nil {
+		return "", err
+	}
+	if len(owned) > 0 {
+		if len(owned) > 1 {
+			extra := make([]string, 0, len(owned)-1)
+			for _, addr := range owned[1:] {
+				extra = append(extra, addr.AllocationID)
+			}
+			l.Info("found multiple EIPs tagged for this object, recovering the first one", "count", len(owned), "extra", extra)
+			r.Record.Eventf(eip, "Warning", "MultipleOwned", "Found %d EIPs tagged for this object, recovering %s, release the extra ones manually: %s",
+				len(owned), owned[0].AllocationID, strings.Join(extra, ", "))
+		}
+		l.Info("recovered previously allocated EIP", "allocationID", owned[0].AllocationID)
+		r.Record.Eventf(eip, "Normal", "Recovered", "Recovered previously allocated EIP: %s", owned[0].AllocationID)
+		return owned[0].AllocationID, nil
 	}
-	if opts.Description == "" {
-		opts.Description = "created by alibabacloud-eip-operator"
+
+	// Apply the webhook defaults again so objects admitted without them are created the same way
+	defaulted := eip.DeepCopy()
+	defaulted.Default()
+	spec := defaulted.Spec
+
+	opts := &aliyunclient.EIPOptions{
+		InternetChargeType:      spec.InternetChargeType,
+		Bandwidth:               spec.Bandwidth,
+		ISP:                     spec.ISP,
+		InstanceChargeType:      spec.InstanceChargeType,
+		PublicIPAddressPoolID:   spec.PublicIPAddressPoolID,
+		ResourceGroupID:         spec.ResourceGroupID,
+		Name:                    spec.Name,
+		Description:             spec.Description,
+		SecurityProtectionTypes: spec.SecurityProtectionTypes,
+		ClientToken:             clientTokenFor(eip),
 	}
 
-	eipAddr, err := r.Aliyun.AllocateEipAddress(ctx, opts)
+	eipAddr, err := api.AllocateEipAddress(ctx, opts)
 	if err != nil {
 		l.Error(err, "failed to allocate EIP")
 		return "", err
@@ -302,26 +658,159 @@ func (r *EIPReconciler) createEIP(ctx context.Context, eip *eipv1alpha1.EIP) (st
 
 	l.Info("EIP created", "allocationID", eipAddr.AllocationID)
 
-	// Tag the EIP if tags are specified
-	if len(eip.Spec.Tags) > 0 {
-		if err := r.Aliyun.TagResources(ctx, "EIP", []string{eipAddr.AllocationID}, eip.Spec.Tags); err != nil {
-			l.Error(err, "failed to tag EIP", "allocationID", eipAddr.AllocationID)
-			// Don't fail the reconciliation for tagging errors
-		}
+	// The ownership tag is what makes the EIP recoverable, so a failure here fails the reconcile.
+	// The retry reuses the ClientToken and gets the same EIP back.
+	tags := make(map[string]string)
+	for k, v := range eip.Spec.Tags {
+		tags[k] = v
+	}
+	for k, v := range ownershipTags(eip, r.ClusterID) {
+		tags[k] = v
+	}
+	for k, v := range operatorTags(r.ClusterID) {
+		tags[k] = v
+	}
+	tags[tagKeyProvenance] = string(eipv1alpha1.EIPProvenanceCreated)
+	if err := api.TagResources(ctx, "EIP", []string{eipAddr.AllocationID}, tags); err != nil {
+		l.Error(err, "failed to tag EIP", "allocationID", eipAddr.AllocationID)
+		return "", err
 	}
 
 	return eipAddr.AllocationID, nil
 }
 
+// reconcileTags converges the cloud tags to spec.tags.
+// Only keys recorded in the managed-tags annotation are removed, tags added by others are left alone.
+func (r *EIPReconciler) reconcileTags(ctx context.Context, api aliyunclient.API, eip *eipv1alpha1.EIP) error {
+	l := log.FromContext(ctx)
+
+	observed, err := api.ListTagResources(ctx, "EIP", eip.Spec.AllocationID)
+	if err != nil {
+		return err
+	}
+
+	// Provenance is decided once, EIPs adopted through spec.allocationID are recorded as imported
+	if eip.Status.Provenance == "" {
+		eip.Status.Provenance = provenanceFromTags(eip, observed)
+		l.Info("recorded EIP provenance", "provenance", eip.Status.Provenance)
+	}
+
+	desired := make(map[string]string, len(eip.Spec.Tags))
+	for k, v := range eip.Spec.Tags {
+		if !isOwnershipTagKey(k) {
+			desired[k] = v
+		}
+	}
+
+	toAdd := make(map[string]string)
+	for k, v := range desired {
+		if current, ok := observed[k]; !ok || current != v {
+			toAdd[k] = v
+		}
+	}
+	if observed[tagKeyProvenance] != string(eip.Status.Provenance) {
+		toAdd[tagKeyProvenance] = string(eip.Status.Provenance)
+	}
+	// Stamp the cluster and object managing the EIP, including adopted ones
+	for k, v := range ownershipTags(eip, r.ClusterID) {
+		if observed[k] != v {
+			toAdd[k] = v
+		}
+	}
+
+	var toRemove []string
+	for _, k := range managedTagKeys(eip) {
+		if _, ok := desired[k]; ok {
+			continue
+		}
+		if _, ok := observed[k]; ok {
+			toRemove = append(toRemove, k)
+		}
+	}
+
+	if len(toAdd) > 0 {
+		l.Info("tagging EIP", "tags", toAdd)
+		if err := api.TagResources(ctx, "EIP", []string{eip.Spec.AllocationID}, toAdd); err != nil {
+			return err
+		}
+		for k, v := range toAdd {
+			observed[k] = v
+		}
+	}
+	if len(toRemove) > 0 {
+		l.Info("untagging EIP", "keys", toRemove)
+		if err := api.UntagResources(ctx, "EIP", []string{eip.Spec.AllocationID}, toRemove); err != nil {
+			return err
+		}
+		for _, k := range toRemove {
+			delete(observed, k)
+		}
+	}
+	if len(toAdd) > 0 || len(toRemove) > 0 {
+		r.Record.Eventf(eip, "Normal", "TagsUpdated", "Updated EIP tags, added or changed %d, removed %d", len(toAdd), len(toRemove))
+	}
+
+	// Record the keys owned by the operator so they can be removed once dropped from spec.tags
+	keys := make([]string, 0, len(desired))
+	for k := range desired {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if !equality.Semantic.DeepEqual(keys, managedTagKeys(eip)) {
+		if err := r.setManagedTagKeys(ctx, eip, keys); err != nil {
+			return err
+		}
+	}
+
+	eip.Status.Tags = observed
+	return nil
+}
+
+// managedTagKeys returns the tag keys recorded as owned by the operator
+func managedTagKeys(eip *eipv1alpha1.EIP) []string {
+	value := eip.Annotations[eipv1alpha1.AnnotationManagedTags]
+	if value == "" {
+		return []string{}
+	}
+
+	var keys []string
+	if err := json.Unmarshal([]byte(value), &keys); err != nil {
+		return []string{}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setManagedTagKeys records the tag keys owned by the operator without losing the in-memory status
+func (r *EIPReconciler) setManagedTagKeys(ctx context.Context, eip *eipv1alpha1.EIP, keys []string) error {
+	value, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(eip.DeepCopy())
+	if eip.Annotations == nil {
+		eip.Annotations = map[string]string{}
+	}
+	eip.Annotations[eipv1alpha1.AnnotationManagedTags] = string(value)
+
+	status := eip.Status.DeepCopy()
+	if err := r.Patch(ctx, eip, patch); err != nil {
+		return err
+	}
+	eip.Status = *status
+	return nil
+}
+
 // syncEIPStatus syncs the EIP status from Aliyun
-func (r *EIPReconciler) syncEIPStatus(ctx context.Context, eip *eipv1alpha1.EIP) error {
+func (r *EIPReconciler) syncEIPStatus(ctx context.Context, api aliyunclient.API, eip *eipv1alpha1.EIP) error {
 	l := log.FromContext(ctx)
 
 	if eip.Spec.AllocationID == "" {
 		return nil
 	}
 
-	eips, err := r.Aliyun.DescribeEipAddresses(ctx, eip.Spec.AllocationID, "", "", "")
+	eips, err := api.DescribeEipAddresses(ctx, eip.Spec.AllocationID, "", "", "")
 	if err != nil {
 		l.Error(err, "failed to describe EIP")
 		return err
@@ -346,6 +835,11 @@ func (r *EIPReconciler) syncEIPStatus(ctx context.Context, eip *eipv1alpha1.EIP)
 	eip.Status.Name = eipInfo.Name
 	eip.Status.PublicIPAddressPoolID = eipInfo.PublicIPAddressPoolID
 	eip.Status.Description = eipInfo.Description
+	eip.Status.InstanceID = eipInfo.InstanceID
+	eip.Status.InstanceType = eipInfo.InstanceType
+	eip.Status.PrivateIPAddress = eipInfo.PrivateIPAddress
+	eip.Status.DeletionProtection = eipInfo.DeletionProtection
+	eip.Status.Tags = eipInfo.Tags
 
 	now := metav1.Now()
 	eip.Status.LastSyncTime = &now
@@ -353,20 +847,64 @@ func (r *EIPReconciler) syncEIPStatus(ctx context.Context, eip *eipv1alpha1.EIP)
 	return r.updateStatus(ctx, eip)
 }
 
-// finalizeEIP handles cleanup when EIP is being deleted
-func (r *EIPReconciler) finalizeEIP(ctx context.Context, eip *eipv1alpha1.EIP) error {
+// finalizeEIP releases the cloud EIP according to releaseStrategy and deletionPolicy,
+// it returns false while the deletion has to wait for the EIP to be disassociated
+func (r *EIPReconciler) finalizeEIP(ctx context.Context, api aliyunclient.API, eip *eipv1alpha1.EIP) (bool, error) {
 	l := log.FromContext(ctx)
 
 	r.setCondition(eip, conditionTypeProgressing, metav1.ConditionTrue, reasonDeleting, "Deleting EIP")
 	_ = r.updateStatus(ctx, eip)
 
-	// Only release EIP if ReleaseStrategy is OnDelete and it was created by operator
-	if eip.Spec.ReleaseStrategy == eipv1alpha1.ReleaseStrategyOnDelete && eip.Status.AllocationID != "" {
+	policy := eip.EffectiveDeletionPolicy()
+
+	// Never release an EIP another object still references
+	var others []eipv1alpha1.EIP
+	if eip.WillBeReleased() && policy != eipv1alpha1.DeletionPolicyOrphan {
+		var err error
+		if others, err = allocationIDConflicts(ctx, r.Client, eip, eip.Status.AllocationID); err != nil {
+			return false, err
+		}
+	}
+
+	// Release according to ReleaseStrategy, OnDeleteIfCreated keeps imported EIPs
+	if len(others) > 0 {
+		l.Info("skipping EIP release, still referenced", "allocationID", eip.Status.AllocationID, "others", eipNames(others))
+		r.Record.Eventf(eip, "Warning", "Skipped", "Skipped EIP release, %s is still referenced by %s", eip.Status.AllocationID, eipNames(others))
+	} else if eip.WillBeReleased() && policy != eipv1alpha1.DeletionPolicyOrphan {
+		// Refresh the binding, it may have changed since the last sync
+		eips, err := api.DescribeEipAddresses(ctx, eip.Status.AllocationID, "", "", "")
+		if err != nil && !isEIPNotFoundError(err) {
+			return false, err
+		}
+		if len(eips) > 0 {
+			if owner, foreign := foreignCluster(eips[0].Tags, r.ClusterID); foreign && !forceOwnership(eip) {
+				l.Info("EIP is owned by another cluster, not releasing", "allocationID", eip.Status.AllocationID, "cluster", owner)
+				r.Record.Eventf(eip, "Warning", reasonOwnedByAnotherCluster, "Not releasing EIP %s owned by cluster %s", eip.Status.AllocationID, owner)
+				r.setCondition(eip, conditionTypeOwnershipConflict, metav1.ConditionTrue, reasonOwnedByAnotherCluster,
+					fmt.Sprintf("EIP %s is owned by cluster %s, set annotation %s=true to release it or %s=Orphan to keep it",
+						eip.Status.AllocationID, owner, eipv1alpha1.AnnotationForceOwnership, eipv1alpha1.AnnotationDeletionPolicyOverride))
+				return false, r.updateStatus(ctx, eip)
+			}
+			if done, err := r.disassociateForRelease(ctx, api, eip, &eips[0], policy); !done || err != nil {
+				return done, err
+			}
+
+			// The release is allowed, lift the cloud deletion protection first
+			if eips[0].DeletionProtection {
+				l.Info("disabling deletion protection before release", "allocationID", eip.Status.AllocationID)
+				if err := api.DeletionProtection(ctx, aliyunclient.DeletionProtectionTypeEIP, eip.Status.AllocationID, false); err != nil {
+					r.Record.Eventf(eip, "Warning", "ReleaseFailed", "Failed to disable deletion protection: %v", err)
+					return false, err
+				}
+				r.Record.Event(eip, "Normal", "Updated", "Disabled deletion protection before release")
+			}
+		}
+
 		l.Info("releasing EIP", "allocationID", eip.Status.AllocationID)
 
 		// Remove from bandwidth package first if needed
 		if eip.Status.BandwidthPackageID != "" {
-			if err := r.Aliyun.RemoveCommonBandwidthPackageIP(ctx, eip.Status.AllocationID, eip.Status.BandwidthPackageID); err != nil {
+			if err := api.RemoveCommonBandwidthPackageIP(ctx, eip.Status.AllocationID, eip.Status.BandwidthPackageID); err != nil {
 				// 如果 EIP 不存在，忽略错误
 				if !isEIPNotFoundError(err) {
 					l.Error(err, "failed to remove EIP from bandwidth package")
@@ -375,7 +913,7 @@ func (r *EIPReconciler) finalizeEIP(ctx context.Context, eip *eipv1alpha1.EIP) e
 			}
 		}
 
-		if err := r.Aliyun.ReleaseEIPAddress(ctx, eip.Status.AllocationID); err != nil {
+		if err := api.ReleaseEIPAddress(ctx, eip.Status.AllocationID); err != nil {
 			// 如果 EIP 已经不存在，认为释放成功
 			if isEIPNotFoundError(err) {
 				l.Info("EIP not found, assuming already released", "allocationID", eip.Status.AllocationID)
@@ -383,19 +921,72 @@ func (r *EIPReconciler) finalizeEIP(ctx context.Context, eip *eipv1alpha1.EIP) e
 			} else {
 				l.Error(err, "failed to release EIP")
 				r.Record.Eventf(eip, "Warning", "ReleaseFailed", "Failed to release EIP: %v", err)
-				return err
+				return false, err
 			}
 		} else {
 			r.Record.Eventf(eip, "Normal", "Released", "Released EIP: %s", eip.Status.AllocationID)
 			l.Info("EIP released", "allocationID", eip.Status.AllocationID)
 		}
 	} else {
-		l.Info("skipping EIP release", "releaseStrategy", eip.Spec.ReleaseStrategy)
-		r.Record.Event(eip, "Normal", "Skipped", "Skipped EIP release due to ReleaseStrategy")
+		if policy == eipv1alpha1.DeletionPolicyOrphan {
+			l.Info("skipping EIP release", "deletionPolicy", policy)
+			r.Record.Event(eip, "Normal", "Skipped", "Skipped EIP release due to DeletionPolicy Orphan")
+		} else {
+			l.Info("skipping EIP release", "releaseStrategy", eip.Spec.ReleaseStrategy, "provenance", eip.Status.Provenance)
+			r.Record.Event(eip, "Normal", "Skipped", "Skipped EIP release due to ReleaseStrategy")
+		}
+
+		// The retained EIP is no longer managed, drop the markers so the orphan collector leaves it alone
+		// and other clusters can adopt it. The cluster tag of another cluster is left untouched.
+		var keys []string
+		if eip.Status.Tags[tagKeyManagedBy] != "" {
+			keys = append(keys, tagKeyManagedBy)
+		}
+		if _, foreign := foreignCluster(eip.Status.Tags, r.ClusterID); !foreign && eip.Status.Tags[tagKeyClusterID] != "" {
+			keys = append(keys, tagKeyClusterID)
+		}
+		if eip.Status.AllocationID != "" && len(keys) > 0 {
+			if err := api.UntagResources(ctx, "EIP", []string{eip.Status.AllocationID}, keys); err != nil && !isEIPNotFoundError(err) {
+				return false, err
+			}
+		}
 	}
 
 	r.setCondition(eip, conditionTypeProgressing, metav1.ConditionFalse, reasonDeleted, "EIP deleted")
-	return nil
+	return true, nil
+}
+
+// disassociateForRelease makes sure the EIP is not bound before it is released,
+// it returns false while the EIP is still bound and the deletion has to wait
+func (r *EIPReconciler) disassociateForRelease(ctx context.Context, api aliyunclient.API, eip *eipv1alpha1.EIP, eipInfo *aliyunclient.EIPAddress, policy eipv1alpha1.DeletionPolicy) (bool, error) {
+	l := log.FromContext(ctx)
+
+	switch {
+	case eipInfo.Status == aliyunclient.EIPStatusAvailable:
+		return true, nil
+
+	case eipInfo.Status == aliyunclient.EIPStatusInUse && policy == eipv1alpha1.DeletionPolicyDisassociateThenRelease:
+		l.Info("disassociating EIP before release", "instanceID", eipInfo.InstanceID, "instanceType", eipInfo.InstanceType)
+		if err := api.UnassociateEipAddress(ctx, eipInfo.AllocationID, eipInfo.InstanceID, eipInfo.InstanceType, eipInfo.PrivateIPAddress); err != nil {
+			r.Record.Eventf(eip, "Warning", "DisassociateFailed", "Failed to disassociate EIP before release: %v", err)
+			return false, err
+		}
+		r.Record.Eventf(eip, "Normal", reasonDisassociating, "Disassociating EIP from %s %s before release", eipInfo.InstanceType, eipInfo.InstanceID)
+		r.setCondition(eip, conditionTypeProgressing, metav1.ConditionTrue, reasonDisassociating,
+			fmt.Sprintf("Disassociating EIP from %s %s before release", eipInfo.InstanceType, eipInfo.InstanceID))
+
+	case eipInfo.Status == aliyunclient.EIPStatusInUse:
+		r.Record.Eventf(eip, "Warning", reasonDeletionBlocked, "EIP is still associated with %s %s", eipInfo.InstanceType, eipInfo.InstanceID)
+		r.setCondition(eip, conditionTypeProgressing, metav1.ConditionTrue, reasonDeletionBlocked,
+			fmt.Sprintf("Deletion is waiting: EIP is still associated with %s %s and deletionPolicy is %s, "+
+				"disassociate it or set annotation %s", eipInfo.InstanceType, eipInfo.InstanceID, policy, eipv1alpha1.AnnotationDeletionPolicyOverride))
+
+	default:
+		r.setCondition(eip, conditionTypeProgressing, metav1.ConditionTrue, reasonDisassociating,
+			fmt.Sprintf("Deletion is waiting for the EIP to leave status %s", eipInfo.Status))
+	}
+
+	return false, r.updateStatus(ctx, eip)
 }
 
 // setCondition sets a condition on the EIP
@@ -421,7 +1012,39 @@ func (r *EIPReconciler) updateStatus(ctx context.Context, eip *eipv1alpha1.EIP)
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *EIPReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &eipv1alpha1.EIP{}, eipBandwidthPackageNameField,
+		func(obj client.Object) []string {
+			return []string{obj.(*eipv1alpha1.EIP).Spec.BandwidthPackageName}
+		}); err != nil {
+		return err
+	}
+	// Also used by the EIP webhook to reject duplicate allocation IDs
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &eipv1alpha1.EIP{}, eipv1alpha1.EIPAllocationIDField,
+		func(obj client.Object) []string {
+			return obj.(*eipv1alpha1.EIP).AllocationIDs()
+		}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&eipv1alpha1.EIP{}).
+		Watches(&eipv1alpha1.BandwidthPackage{}, handler.EnqueueRequestsFromMapFunc(r.eipsForBandwidthPackage)).
 		Complete(r)
 }
+
+// eipsForBandwidthPackage maps a BandwidthPackage to the EIPs referencing it by name
+func (r *EIPReconciler) eipsForBandwidthPackage(ctx context.Context, obj client.Object) []reconcile.Request {
+	eips := &eipv1alpha1.EIPList{}
+	if err := r.List(ctx, eips, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{eipBandwidthPackageNameField: obj.GetName()}); err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(eips.Items))
+	for _, eip := range eips.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: eip.Namespace, Name: eip.Name},
+		})
+	}
+	return requests
+}
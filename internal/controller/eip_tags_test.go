@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+// userTags drops the tags the operator stamps for itself
+func userTags(tags map[string]string) map[string]string {
+	user := map[string]string{}
+	for k, v := range tags {
+		if !strings.HasPrefix(k, "eip.alibabacloud.com/") {
+			user[k] = v
+		}
+	}
+	return user
+}
+
+func TestReconcileTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     map[string]string
+		observed map[string]string
+		// managed is the managed-tags annotation before the reconcile
+		managed string
+
+		want        map[string]string
+		wantManaged string
+	}{
+		{
+			name:        "spec tags are added",
+			spec:        map[string]string{"env": "prod"},
+			want:        map[string]string{"env": "prod"},
+			wantManaged: `["env"]`,
+		},
+		{
+			name:        "changed values are updated",
+			spec:        map[string]string{"env": "prod"},
+			observed:    map[string]string{"env": "test"},
+			managed:     `["env"]`,
+			want:        map[string]string{"env": "prod"},
+			wantManaged: `["env"]`,
+		},
+		{
+			name:        "tags dropped from spec are removed",
+			observed:    map[string]string{"env": "prod", "team": "web"},
+			managed:     `["env","team"]`,
+			want:        map[string]string{},
+			wantManaged: `[]`,
+		},
+		{
+			name:        "tags set outside the operator are kept",
+			spec:        map[string]string{"env": "prod"},
+			observed:    map[string]string{"owner": "alice"},
+			want:        map[string]string{"env": "prod", "owner": "alice"},
+			wantManaged: `["env"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := newFakeCloud()
+			cloud.tags["eip-1"] = map[string]string{}
+			for k, v := range tt.observed {
+				cloud.tags["eip-1"][k] = v
+			}
+
+			eip := &eipv1alpha1.EIP{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "eip-uid"},
+				Spec:       eipv1alpha1.EIPSpec{AllocationID: "eip-1", Tags: tt.spec},
+			}
+			if tt.managed != "" {
+				eip.Annotations = map[string]string{eipv1alpha1.AnnotationManagedTags: tt.managed}
+			}
+			c := newFakeClient(eip)
+			r := &EIPReconciler{Client: c, Scheme: c.Scheme(), Record: record.NewFakeRecorder(100)}
+
+			if err := r.reconcileTags(ctx, cloud, eip); err != nil {
+				t.Fatalf("reconcileTags() error = %v", err)
+			}
+			if got := userTags(cloud.tags["eip-1"]); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cloud tags = %v, want %v", got, tt.want)
+			}
+			if got := userTags(eip.Status.Tags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("status tags = %v, want %v", got, tt.want)
+			}
+			if got := eip.Annotations[eipv1alpha1.AnnotationManagedTags]; got != tt.wantManaged {
+				t.Errorf("managed tags = %s, want %s", got, tt.wantManaged)
+			}
+		})
+	}
+}
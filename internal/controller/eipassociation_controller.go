@@ -0,0 +1,376 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+const (
+	eipAssociationFinalizer = "eip.alibabacloud.com/association-finalizer"
+
+	// eipAssociationEIPNameField 按引用的EIP名称索引EIPAssociation
+	eipAssociationEIPNameField = ".spec.eipName"
+
+	// Reasons
+	reasonEIPNotReady     = "EIPNotReady"
+	reasonAssociating     = "Associating"
+	reasonAssociated      = "Associated"
+	reasonUnassociating   = "Unassociating"
+	reasonInstanceInUse   = "InstanceConflict"
+	reasonAssociateFailed = "AssociateFailed"
+)
+
+const (
+	// 绑定/解绑为异步操作，使用较短的轮询间隔
+	eipAssociationRequeueAfterPending = 5 * time.Second
+)
+
+// EIPAssociationReconciler reconciles a EIPAssociation object
+type EIPAssociationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Record record.EventRecorder
+	// Accounts 按EIP所属的CloudAccount选择阿里云客户端
+	Accounts *CloudAccountClients
+}
+
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eipassociations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eipassociations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eipassociations/finalizers,verbs=update
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eips,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop
+func (r *EIPAssociationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	assoc := &eipv1alpha1.EIPAssociation{}
+	err := r.Get(ctx, req.NamespacedName, assoc)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Check if the EIPAssociation instance is marked to be deleted
+	if !assoc.ObjectMeta.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(assoc, eipAssociationFinalizer) {
+			done, err := r.unassociate(ctx, assoc)
+			if err != nil {
+				if isThrottlingError(err) {
+					return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterThrottle}, nil
+				}
+				return ctrl.Result{}, err
+			}
+			if !done {
+				return ctrl.Result{RequeueAfter: eipAssociationRequeueAfterPending}, nil
+			}
+
+			controllerutil.RemoveFinalizer(assoc, eipAssociationFinalizer)
+			if err := r.Update(ctx, assoc); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer if not present
+	if !controllerutil.ContainsFinalizer(assoc, eipAssociationFinalizer) {
+		controllerutil.AddFinalizer(assoc, eipAssociationFinalizer)
+		if err := r.Update(ctx, assoc); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	result, err := r.reconcileAssociation(ctx, assoc)
+	if err != nil {
+		if isThrottlingError(err) {
+			l.Info("API throttled, will retry later")
+			r.setCondition(assoc, conditionTypeReady, metav1.ConditionFalse, reasonThrottled, "API throttled, retrying later")
+			_ = r.updateStatus(ctx, assoc)
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterThrottle}, nil
+		}
+		l.Error(err, "failed to reconcile EIPAssociation")
+		r.Record.Eventf(assoc, "Warning", "ReconcileFailed", "Failed to reconcile EIPAssociation: %v", err)
+		r.setCondition(assoc, conditionTypeReady, metav1.ConditionFalse, reasonSyncFailed, err.Error())
+		_ = r.updateStatus(ctx, assoc)
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
+	}
+
+	return result, nil
+}
+
+// reconcileAssociation drives the referenced EIP towards the desired binding
+func (r *EIPAssociationReconciler) reconcileAssociation(ctx context.Context, assoc *eipv1alpha1.EIPAssociation) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	eip := &eipv1alpha1.EIP{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: assoc.Namespace, Name: assoc.Spec.EIPName}, eip); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		r.setCondition(assoc, conditionTypeReady, metav1.ConditionFalse, reasonEIPNotReady, fmt.Sprintf("EIP %s not found", assoc.Spec.EIPName))
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, assoc)
+	}
+	if eip.Status.AllocationID == "" {
+		r.setCondition(assoc, conditionTypeReady, metav1.ConditionFalse, reasonEIPNotReady, fmt.Sprintf("EIP %s has not been allocated yet", eip.Name))
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, assoc)
+	}
+	// The EIP finalizer may be disassociating it for release, do not bind it again
+	if !eip.DeletionTimestamp.IsZero() {
+		r.setCondition(assoc, conditionTypeReady, metav1.ConditionFalse, reasonEIPNotReady, fmt.Sprintf("EIP %s is being deleted", eip.Name))
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, assoc)
+	}
+
+	// The spec or the referenced EIP changed, release the previous binding first
+	if assoc.Status.InstanceID != "" && !r.isDesiredBinding(assoc, eip.Status.AllocationID) {
+		l.Info("binding changed, unassociating previous instance",
+			"allocationID", assoc.Status.AllocationID, "instanceID", assoc.Status.InstanceID)
+		done, err := r.unassociate(ctx, assoc)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			return ctrl.Result{RequeueAfter: eipAssociationRequeueAfterPending}, nil
+		}
+	}
+
+	clients, err := r.Accounts.ForAccount(eip.Status.CloudAccountName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	regionID := eipRegion(eip, clients)
+	api, err := clients.ForRegion(regionID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	eips, err := api.DescribeEipAddresses(ctx, eip.Status.AllocationID, "", "", "")
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(eips) != 1 {
+		return ctrl.Result{}, fmt.Errorf("expected 1 EIP, got %d", len(eips))
+	}
+	eipInfo := eips[0]
+
+	assoc.Status.AllocationID = eipInfo.AllocationID
+	assoc.Status.RegionID = regionID
+	assoc.Status.CloudAccountName = eip.Status.CloudAccountName
+	assoc.Status.EIPAddress = eipInfo.IPAddress
+	assoc.Status.Status = eipInfo.Status
+	now := metav1.Now()
+	assoc.Status.LastSyncTime = &now
+
+	switch eipInfo.Status {
+	case aliyunclient.EIPStatusAssociating, aliyunclient.EIPStatusUnassociating:
+		r.setCondition(assoc, conditionTypeProgressing, metav1.ConditionTrue, eipInfo.Status, fmt.Sprintf("EIP is %s", eipInfo.Status))
+		return ctrl.Result{RequeueAfter: eipAssociationRequeueAfterPending}, r.updateStatus(ctx, assoc)
+
+	case aliyunclient.EIPStatusInUse:
+		if eipInfo.InstanceID != assoc.Spec.InstanceID {
+			r.setCondition(assoc, conditionTypeReady, metav1.ConditionFalse, reasonInstanceInUse,
+				fmt.Sprintf("EIP is associated with another instance %s (%s)", eipInfo.InstanceID, eipInfo.InstanceType))
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, assoc)
+		}
+
+		if !apimeta.IsStatusConditionTrue(assoc.Status.Conditions, conditionTypeReady) {
+			r.Record.Eventf(assoc, "Normal", "Associated", "Associated EIP %s with %s %s", eipInfo.IPAddress, eipInfo.InstanceType, eipInfo.InstanceID)
+		}
+		assoc.Status.InstanceID = eipInfo.InstanceID
+		assoc.Status.InstanceType = eipInfo.InstanceType
+		assoc.Status.PrivateIPAddress = eipInfo.PrivateIPAddress
+		r.setCondition(assoc, conditionTypeProgressing, metav1.ConditionFalse, reasonAssociated, "EIP associated")
+		r.setCondition(assoc, conditionTypeReady, metav1.ConditionTrue, reasonAssociated, "EIP is associated")
+		if err := r.updateStatus(ctx, assoc); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+
+	case aliyunclient.EIPStatusAvailable:
+		l.Info("associating EIP", "allocationID", eipInfo.AllocationID,
+			"instanceID", assoc.Spec.InstanceID, "instanceType", assoc.Spec.InstanceType)
+		// 先在status中记录目标绑定，绑定完成前删除EIPAssociation也能解绑
+		assoc.Status.InstanceID = assoc.Spec.InstanceID
+		assoc.Status.InstanceType = string(assoc.Spec.InstanceType)
+		assoc.Status.PrivateIPAddress = assoc.Spec.PrivateIPAddress
+		r.setCondition(assoc, conditionTypeProgressing, metav1.ConditionTrue, reasonAssociating, "Associating EIP")
+		r.setCondition(assoc, conditionTypeReady, metav1.ConditionFalse, reasonAssociating, "Associating EIP")
+		if err := r.updateStatus(ctx, assoc); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if err := api.AssociateEipAddress(ctx, eipInfo.AllocationID, assoc.Spec.InstanceID,
+			string(assoc.Spec.InstanceType), assoc.Spec.PrivateIPAddress); err != nil {
+			if isThrottlingError(err) {
+				return ctrl.Result{}, err
+			}
+			r.Record.Eventf(assoc, "Warning", reasonAssociateFailed, "Failed to associate EIP: %v", err)
+			r.setCondition(assoc, conditionTypeReady, metav1.ConditionFalse, reasonAssociateFailed, fmt.Sprintf("Failed to associate EIP: %v", err))
+			_ = r.updateStatus(ctx, assoc)
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, nil
+		}
+		return ctrl.Result{RequeueAfter: eipAssociationRequeueAfterPending}, nil
+	}
+
+	r.setCondition(assoc, conditionTypeReady, metav1.ConditionFalse, reasonEIPNotReady, fmt.Sprintf("EIP is in unexpected status %s", eipInfo.Status))
+	return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, assoc)
+}
+
+// isDesiredBinding reports whether the binding recorded in status matches the spec
+func (r *EIPAssociationReconciler) isDesiredBinding(assoc *eipv1alpha1.EIPAssociation, allocationID string) bool {
+	return assoc.Status.AllocationID == allocationID &&
+		assoc.Status.InstanceID == assoc.Spec.InstanceID &&
+		(assoc.Spec.PrivateIPAddress == "" || assoc.Status.PrivateIPAddress == assoc.Spec.PrivateIPAddress)
+}
+
+// unassociate unbinds the EIP recorded in status, it returns true once the EIP is no longer bound
+func (r *EIPAssociationReconciler) unassociate(ctx context.Context, assoc *eipv1alpha1.EIPAssociation) (bool, error) {
+	l := log.FromContext(ctx)
+
+	if assoc.Status.AllocationID == "" || assoc.Status.InstanceID == "" {
+		return true, nil
+	}
+
+	// Bindings recorded before multi-region support have no region and live in the default one
+	clients, err := r.Accounts.ForAccount(assoc.Status.CloudAccountName)
+	if err != nil {
+		return false, err
+	}
+	api, err := clients.ForRegion(assoc.Status.RegionID)
+	if err != nil {
+		return false, err
+	}
+	eips, err := api.DescribeEipAddresses(ctx, assoc.Status.AllocationID, "", "", "")
+	if err != nil {
+		if isEIPNotFoundError(err) {
+			r.clearBinding(assoc)
+			return true, r.updateStatus(ctx, assoc)
+		}
+		return false, err
+	}
+	if len(eips) == 0 {
+		r.clearBinding(assoc)
+		return true, r.updateStatus(ctx, assoc)
+	}
+	eipInfo := eips[0]
+
+	switch {
+	case eipInfo.Status == aliyunclient.EIPStatusAssociating || eipInfo.Status == aliyunclient.EIPStatusUnassociating:
+		return false, nil
+	case eipInfo.Status != aliyunclient.EIPStatusInUse || eipInfo.InstanceID != assoc.Status.InstanceID:
+		// Already unbound or bound by someone else, nothing left for us to release
+		r.clearBinding(assoc)
+		return true, r.updateStatus(ctx, assoc)
+	}
+
+	l.Info("unassociating EIP", "allocationID", eipInfo.AllocationID, "instanceID", eipInfo.InstanceID)
+	r.setCondition(assoc, conditionTypeProgressing, metav1.ConditionTrue, reasonUnassociating, "Unassociating EIP")
+	_ = r.updateStatus(ctx, assoc)
+
+	if err := api.UnassociateEipAddress(ctx, eipInfo.AllocationID, eipInfo.InstanceID,
+		eipInfo.InstanceType, eipInfo.PrivateIPAddress); err != nil {
+		if isEIPNotFoundError(err) {
+			r.clearBinding(assoc)
+			return true, r.updateStatus(ctx, assoc)
+		}
+		r.Record.Eventf(assoc, "Warning", "UnassociateFailed", "Failed to unassociate EIP: %v", err)
+		return false, err
+	}
+
+	r.Record.Eventf(assoc, "Normal", "Unassociated", "Unassociated EIP %s from %s", eipInfo.IPAddress, eipInfo.InstanceID)
+	return false, nil
+}
+
+// clearBinding forgets the binding recorded in status
+func (r *EIPAssociationReconciler) clearBinding(assoc *eipv1alpha1.EIPAssociation) {
+	assoc.Status.InstanceID = ""
+	assoc.Status.InstanceType = ""
+	assoc.Status.PrivateIPAddress = ""
+}
+
+// setCondition sets a condition on the EIPAssociation
+func (r *EIPAssociationReconciler) setCondition(assoc *eipv1alpha1.EIPAssociation, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: assoc.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	apimeta.SetStatusCondition(&assoc.Status.Conditions, condition)
+}
+
+// updateStatus updates the EIPAssociation status
+func (r *EIPAssociationReconciler) updateStatus(ctx context.Context, assoc *eipv1alpha1.EIPAssociation) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return r.Status().Update(ctx, assoc)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *EIPAssociationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &eipv1alpha1.EIPAssociation{}, eipAssociationEIPNameField,
+		func(obj client.Object) []string {
+			return []string{obj.(*eipv1alpha1.EIPAssociation).Spec.EIPName}
+		}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&eipv1alpha1.EIPAssociation{}).
+		Watches(&eipv1alpha1.EIP{}, handler.EnqueueRequestsFromMapFunc(r.associationsForEIP)).
+		Complete(r)
+}
+
+// associationsForEIP maps an EIP to the EIPAssociations referencing it
+func (r *EIPAssociationReconciler) associationsForEIP(ctx context.Context, obj client.Object) []reconcile.Request {
+	assocs := &eipv1alpha1.EIPAssociationList{}
+	if err := r.List(ctx, assocs, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{eipAssociationEIPNameField: obj.GetName()}); err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(assocs.Items))
+	for _, assoc := range assocs.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: assoc.Namespace, Name: assoc.Name},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+// newAssociationTest returns an association reconciler for an allocated EIP web bound to instanceID in the cloud
+func newAssociationTest(instanceID string) (*EIPAssociationReconciler, *fakeCloud) {
+	cloud := newFakeCloud()
+	cloud.eips["eip-1"] = &aliyunclient.EIPAddress{AllocationID: "eip-1", IPAddress: "47.0.0.1", Status: aliyunclient.EIPStatusAvailable}
+	if instanceID != "" {
+		cloud.eips["eip-1"].Status = aliyunclient.EIPStatusInUse
+		cloud.eips["eip-1"].InstanceID = instanceID
+		cloud.eips["eip-1"].InstanceType = "EcsInstance"
+	}
+
+	eip := &eipv1alpha1.EIP{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Status:     eipv1alpha1.EIPStatus{AllocationID: "eip-1"},
+	}
+	assoc := &eipv1alpha1.EIPAssociation{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       eipv1alpha1.EIPAssociationSpec{EIPName: "web", InstanceID: "i-1", InstanceType: "EcsInstance"},
+	}
+	c := newFakeClient(eip, assoc)
+	return &EIPAssociationReconciler{
+		Client:   c,
+		Scheme:   c.Scheme(),
+		Record:   record.NewFakeRecorder(100),
+		Accounts: accountsFor(c, cloud),
+	}, cloud
+}
+
+func TestEIPAssociationAssociates(t *testing.T) {
+	ctx := context.Background()
+	r, cloud := newAssociationTest("")
+	assoc := &eipv1alpha1.EIPAssociation{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+
+	if err := reconcileN(ctx, r, assoc, 2); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if n := cloud.called("AssociateEipAddress eip-1 i-1"); n != 1 {
+		t.Errorf("AssociateEipAddress called %d times, want 1", n)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(assoc), assoc); err != nil {
+		t.Fatal(err)
+	}
+	if assoc.Status.InstanceID != "i-1" || !apimeta.IsStatusConditionTrue(assoc.Status.Conditions, conditionTypeReady) {
+		t.Errorf("status = %+v, want ready binding to i-1", assoc.Status)
+	}
+}
+
+func TestEIPAssociationDeletedBeforeBound(t *testing.T) {
+	ctx := context.Background()
+	r, cloud := newAssociationTest("")
+	assoc := &eipv1alpha1.EIPAssociation{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+
+	// The object goes away right after the associate call, before any reconcile saw InUse
+	if err := reconcileN(ctx, r, assoc, 1); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := r.Delete(ctx, assoc); err != nil {
+		t.Fatal(err)
+	}
+	if err := reconcileN(ctx, r, assoc, 2); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if n := cloud.called("UnassociateEipAddress eip-1 i-1"); n != 1 {
+		t.Errorf("UnassociateEipAddress called %d times, want 1", n)
+	}
+	if cloud.eips["eip-1"].Status != aliyunclient.EIPStatusAvailable {
+		t.Errorf("EIP status = %s, want Available", cloud.eips["eip-1"].Status)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(assoc), assoc); !errors.IsNotFound(err) {
+		t.Errorf("Get() error = %v, want the finalizer removed and the object gone", err)
+	}
+}
+
+func TestEIPAssociationKeepsForeignBinding(t *testing.T) {
+	ctx := context.Background()
+	r, cloud := newAssociationTest("i-2")
+	assoc := &eipv1alpha1.EIPAssociation{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+
+	if err := reconcileN(ctx, r, assoc, 1); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(assoc), assoc); err != nil {
+		t.Fatal(err)
+	}
+	if cond := apimeta.FindStatusCondition(assoc.Status.Conditions, conditionTypeReady); cond == nil || cond.Reason != reasonInstanceInUse {
+		t.Errorf("Ready condition = %+v, want reason %s", cond, reasonInstanceInUse)
+	}
+
+	if err := r.Delete(ctx, assoc); err != nil {
+		t.Fatal(err)
+	}
+	if err := reconcileN(ctx, r, assoc, 1); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(cloud.calls) != 0 {
+		t.Errorf("cloud calls = %v, want none", cloud.calls)
+	}
+	if cloud.eips["eip-1"].InstanceID != "i-2" {
+		t.Errorf("EIP bound to %s, want i-2 untouched", cloud.eips["eip-1"].InstanceID)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(assoc), assoc); !errors.IsNotFound(err) {
+		t.Errorf("Get() error = %v, want the object gone", err)
+	}
+}
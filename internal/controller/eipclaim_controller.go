@@ -0,0 +1,402 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+const (
+	eipClaimFinalizer = "eip.alibabacloud.com/claim-finalizer"
+
+	// eipClaimKind 写入EIP占用标记时使用的资源类型
+	eipClaimKind = "EIPClaim"
+
+	// Condition types
+	conditionTypeBound = "Bound"
+
+	// Reasons
+	reasonBound           = "Bound"
+	reasonWaitingForEIP   = "WaitingForEIP"
+	reasonEIPLost         = "EIPLost"
+	reasonClassNotFound   = "ClassNotFound"
+	reasonPoolExhausted   = "PoolExhausted"
+	reasonEIPNotAvailable = "EIPNotAvailable"
+)
+
+// EIPClaimReconciler reconciles a EIPClaim object
+type EIPClaimReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Record record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eipclaims,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eipclaims/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eipclaims/finalizers,verbs=update
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eipclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eips,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop
+func (r *EIPClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	claim := &eipv1alpha1.EIPClaim{}
+	err := r.Get(ctx, req.NamespacedName, claim)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Check if the EIPClaim instance is marked to be deleted
+	if !claim.ObjectMeta.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(claim, eipClaimFinalizer) {
+			if err := r.reclaim(ctx, claim); err != nil {
+				return ctrl.Result{}, err
+			}
+
+			controllerutil.RemoveFinalizer(claim, eipClaimFinalizer)
+			if err := r.Update(ctx, claim); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer if not present
+	if !controllerutil.ContainsFinalizer(claim, eipClaimFinalizer) {
+		controllerutil.AddFinalizer(claim, eipClaimFinalizer)
+		if err := r.Update(ctx, claim); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	result, err := r.bind(ctx, claim)
+	if err != nil {
+		l.Error(err, "failed to bind EIPClaim")
+		r.Record.Eventf(claim, "Warning", "ReconcileFailed", "Failed to bind EIPClaim: %v", err)
+		r.setCondition(claim, conditionTypeBound, metav1.ConditionFalse, reasonSyncFailed, err.Error())
+		_ = r.updateStatus(ctx, claim)
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
+	}
+
+	return result, nil
+}
+
+// bind binds the claim to an EIP and mirrors the EIP address into the claim status
+func (r *EIPClaimReconciler) bind(ctx context.Context, claim *eipv1alpha1.EIPClaim) (ctrl.Result, error) {
+	claimant := claimantFor(eipClaimKind, claim.Name)
+
+	if claim.Status.EIPName == "" {
+		// Recover a binding whose status update was lost
+		eip, err := findClaimedEIP(ctx, r.Client, claim.Namespace, claimant)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if eip == nil {
+			eip, err = r.selectEIP(ctx, claim, claimant)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if eip == nil {
+			claim.Status.Phase = eipv1alpha1.EIPClaimPending
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, claim)
+		}
+
+		policy, err := r.reclaimPolicyFor(ctx, claim)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		claim.Status.EIPName = eip.Name
+		claim.Status.ReclaimPolicy = policy
+		r.Record.Eventf(claim, "Normal", "Bound", "Bound to EIP %s", eip.Name)
+	}
+
+	eip := &eipv1alpha1.EIP{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: claim.Namespace, Name: claim.Status.EIPName}, eip); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		claim.Status.Phase = eipv1alpha1.EIPClaimLost
+		r.setCondition(claim, conditionTypeBound, metav1.ConditionFalse, reasonEIPLost, fmt.Sprintf("Bound EIP %s no longer exists", claim.Status.EIPName))
+		return ctrl.Result{}, r.updateStatus(ctx, claim)
+	}
+
+	switch eip.Annotations[eipv1alpha1.AnnotationClaimedBy] {
+	case claimant:
+	case "":
+		// The claim marker was removed by hand, take the EIP back
+		ok, err := claimEIP(ctx, r.Client, eip, claimant)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !ok {
+			// The EIP changed meanwhile and may have been claimed by someone else, check again on the latest version
+			return ctrl.Result{Requeue: true}, nil
+		}
+	default:
+		claim.Status.Phase = eipv1alpha1.EIPClaimLost
+		r.setCondition(claim, conditionTypeBound, metav1.ConditionFalse, reasonEIPLost,
+			fmt.Sprintf("EIP %s is claimed by %s", eip.Name, eip.Annotations[eipv1alpha1.AnnotationClaimedBy]))
+		return ctrl.Result{}, r.updateStatus(ctx, claim)
+	}
+
+	claim.Status.Phase = eipv1alpha1.EIPClaimBound
+	claim.Status.AllocationID = eip.Status.AllocationID
+	claim.Status.EIPAddress = eip.Status.EIPAddress
+	if eip.Status.EIPAddress == "" {
+		r.setCondition(claim, conditionTypeBound, metav1.ConditionTrue, reasonBound, fmt.Sprintf("Bound to EIP %s", eip.Name))
+		r.setCondition(claim, conditionTypeReady, metav1.ConditionFalse, reasonWaitingForEIP, fmt.Sprintf("Waiting for EIP %s to be allocated", eip.Name))
+		return ctrl.Result{}, r.updateStatus(ctx, claim)
+	}
+
+	r.setCondition(claim, conditionTypeBound, metav1.ConditionTrue, reasonBound, fmt.Sprintf("Bound to EIP %s", eip.Name))
+	r.setCondition(claim, conditionTypeReady, metav1.ConditionTrue, "Available", fmt.Sprintf("EIP address %s is ready", eip.Status.EIPAddress))
+	return ctrl.Result{}, r.updateStatus(ctx, claim)
+}
+
+// selectEIP claims an EIP for the claim, it returns nil and records the reason when none can be bound yet
+func (r *EIPClaimReconciler) selectEIP(ctx context.Context, claim *eipv1alpha1.EIPClaim, claimant string) (*eipv1alpha1.EIP, error) {
+	switch {
+	case claim.Spec.EIPName != "":
+		eip := &eipv1alpha1.EIP{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: claim.Namespace, Name: claim.Spec.EIPName}, eip); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			r.setCondition(claim, conditionTypeBound, metav1.ConditionFalse, reasonEIPNotAvailable, fmt.Sprintf("EIP %s not found", claim.Spec.EIPName))
+			return nil, nil
+		}
+		ok, err := claimEIP(ctx, r.Client, eip, claimant)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			r.setCondition(claim, conditionTypeBound, metav1.ConditionFalse, reasonEIPNotAvailable,
+				fmt.Sprintf("EIP %s is claimed by %s", eip.Name, eip.Annotations[eipv1alpha1.AnnotationClaimedBy]))
+			return nil, nil
+		}
+		return eip, nil
+
+	case claim.Spec.PoolName != "":
+		eip, err := claimAvailableEIP(ctx, r.Client, claim.Namespace,
+			map[string]string{eipv1alpha1.LabelEIPPool: claim.Spec.PoolName}, claimant)
+		if err != nil {
+			return nil, err
+		}
+		if eip == nil {
+			r.setCondition(claim, conditionTypeBound, metav1.ConditionFalse, reasonPoolExhausted,
+				fmt.Sprintf("No available EIP in pool %s", claim.Spec.PoolName))
+		}
+		return eip, nil
+
+	case claim.Spec.ClassName != "":
+		class := &eipv1alpha1.EIPClass{}
+		if err := r.Get(ctx, types.NamespacedName{Name: claim.Spec.ClassName}, class); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			r.setCondition(claim, conditionTypeBound, metav1.ConditionFalse, reasonClassNotFound, fmt.Sprintf("EIPClass %s not found", claim.Spec.ClassName))
+			return nil, nil
+		}
+
+		eip, err := claimAvailableEIP(ctx, r.Client, claim.Namespace,
+			map[string]string{eipv1alpha1.LabelEIPClass: class.Name}, claimant)
+		if err != nil || eip != nil {
+			return eip, err
+		}
+		return r.provisionEIP(ctx, claim, class, claimant)
+	}
+
+	r.setCondition(claim, conditionTypeBound, metav1.ConditionFalse, reasonInvalidSpec, "One of className, poolName or eipName must be set")
+	return nil, nil
+}
+
+// provisionEIP creates a new EIP from the class template, already claimed by claimant
+func (r *EIPClaimReconciler) provisionEIP(ctx context.Context, claim *eipv1alpha1.EIPClaim, class *eipv1alpha1.EIPClass, claimant string) (*eipv1alpha1.EIP, error) {
+	template := class.Spec.Template.DeepCopy()
+
+	labels := template.Metadata.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[eipv1alpha1.LabelEIPClass] = class.Name
+
+	annotations := template.Metadata.Annotations
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[eipv1alpha1.AnnotationClaimedBy] = claimant
+
+	eip := &eipv1alpha1.EIP{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: strings.ToLower(claim.Name) + "-",
+			Namespace:    claim.Namespace,
+			Labels:       labels,
+			Annotations:  annotations,
+		},
+		Spec: template.Spec,
+	}
+	eip.Spec.AllocationID = ""
+
+	if err := r.Create(ctx, eip); err != nil {
+		return nil, fmt.Errorf("failed to provision EIP from class %s: %w", class.Name, err)
+	}
+	r.Record.Eventf(claim, "Normal", "Provisioned", "Provisioned EIP %s from class %s", eip.Name, class.Name)
+	return eip, nil
+}
+
+// reclaimPolicyFor resolves the reclaim policy of the claim
+func (r *EIPClaimReconciler) reclaimPolicyFor(ctx context.Context, claim *eipv1alpha1.EIPClaim) (eipv1alpha1.EIPReclaimPolicy, error) {
+	if claim.Spec.ReclaimPolicy != "" {
+		return claim.Spec.ReclaimPolicy, nil
+	}
+
+	if claim.Spec.ClassName != "" && claim.Spec.EIPName == "" && claim.Spec.PoolName == "" {
+		class := &eipv1alpha1.EIPClass{}
+		if err := r.Get(ctx, types.NamespacedName{Name: claim.Spec.ClassName}, class); err != nil {
+			return "", client.IgnoreNotFound(err)
+		}
+		if class.Spec.ReclaimPolicy != "" {
+			return class.Spec.ReclaimPolicy, nil
+		}
+		return eipv1alpha1.EIPReclaimPolicyDelete, nil
+	}
+
+	return eipv1alpha1.EIPReclaimPolicyRecycle, nil
+}
+
+// reclaim applies the reclaim policy to the bound EIP
+func (r *EIPClaimReconciler) reclaim(ctx context.Context, claim *eipv1alpha1.EIPClaim) error {
+	l := log.FromContext(ctx)
+
+	if claim.Status.EIPName == "" {
+		return nil
+	}
+
+	claimant := claimantFor(eipClaimKind, claim.Name)
+	key := types.NamespacedName{Namespace: claim.Namespace, Name: claim.Status.EIPName}
+
+	eip := &eipv1alpha1.EIP{}
+	if err := r.Get(ctx, key, eip); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if eip.Annotations[eipv1alpha1.AnnotationClaimedBy] != claimant {
+		return nil
+	}
+
+	policy := claim.Status.ReclaimPolicy
+	if policy == "" {
+		var err error
+		if policy, err = r.reclaimPolicyFor(ctx, claim); err != nil {
+			return err
+		}
+	}
+
+	l.Info("reclaiming EIP", "eip", eip.Name, "reclaimPolicy", policy)
+	switch policy {
+	case eipv1alpha1.EIPReclaimPolicyRetain:
+		r.Record.Eventf(claim, "Normal", "Retained", "Retained EIP %s", eip.Name)
+	case eipv1alpha1.EIPReclaimPolicyRecycle:
+		if err := unclaimEIP(ctx, r.Client, key, claimant); err != nil {
+			return err
+		}
+		r.Record.Eventf(claim, "Normal", "Recycled", "Returned EIP %s", eip.Name)
+	default:
+		if err := r.Delete(ctx, eip); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		r.Record.Eventf(claim, "Normal", "Deleted", "Deleted EIP %s", eip.Name)
+	}
+	return nil
+}
+
+// setCondition sets a condition on the EIPClaim
+func (r *EIPClaimReconciler) setCondition(claim *eipv1alpha1.EIPClaim, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: claim.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	apimeta.SetStatusCondition(&claim.Status.Conditions, condition)
+}
+
+// updateStatus updates the EIPClaim status
+func (r *EIPClaimReconciler) updateStatus(ctx context.Context, claim *eipv1alpha1.EIPClaim) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return r.Status().Update(ctx, claim)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *EIPClaimReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&eipv1alpha1.EIPClaim{}).
+		Watches(&eipv1alpha1.EIP{}, handler.EnqueueRequestsFromMapFunc(r.claimsForEIP)).
+		Complete(r)
+}
+
+// claimsForEIP maps an EIP to its claim, or to the pending claims of the namespace when the EIP is free
+func (r *EIPClaimReconciler) claimsForEIP(ctx context.Context, obj client.Object) []reconcile.Request {
+	if claimant := obj.GetAnnotations()[eipv1alpha1.AnnotationClaimedBy]; claimant != "" {
+		name, found := strings.CutPrefix(claimant, eipClaimKind+"/")
+		if !found {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+	}
+
+	claims := &eipv1alpha1.EIPClaimList{}
+	if err := r.List(ctx, claims, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, claim := range claims.Items {
+		if claim.Status.EIPName == "" {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: claim.Namespace, Name: claim.Name},
+			})
+		}
+	}
+	return requests
+}
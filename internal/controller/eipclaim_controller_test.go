@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+// boundClaim returns a claim bound to EIP web and the EIP, claimedBy is the claimant recorded on the EIP
+func boundClaim(policy eipv1alpha1.EIPReclaimPolicy, claimedBy string) (*eipv1alpha1.EIPClaim, *eipv1alpha1.EIP) {
+	claim := &eipv1alpha1.EIPClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default", Finalizers: []string{eipClaimFinalizer}},
+		Spec:       eipv1alpha1.EIPClaimSpec{EIPName: "web"},
+		Status:     eipv1alpha1.EIPClaimStatus{EIPName: "web", ReclaimPolicy: policy},
+	}
+	eip := &eipv1alpha1.EIP{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Status:     eipv1alpha1.EIPStatus{AllocationID: "eip-1", EIPAddress: "47.0.0.1"},
+	}
+	if claimedBy != "" {
+		eip.Annotations = map[string]string{eipv1alpha1.AnnotationClaimedBy: claimedBy}
+	}
+	return claim, eip
+}
+
+func newClaimReconciler(c client.Client) *EIPClaimReconciler {
+	return &EIPClaimReconciler{Client: c, Scheme: c.Scheme(), Record: record.NewFakeRecorder(100)}
+}
+
+func TestEIPClaimReclaim(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      eipv1alpha1.EIPReclaimPolicy
+		claimedBy   string
+		wantDeleted bool
+		wantClaimed string
+	}{
+		{
+			name:        "retain keeps the EIP claimed",
+			policy:      eipv1alpha1.EIPReclaimPolicyRetain,
+			claimedBy:   "EIPClaim/claim",
+			wantClaimed: "EIPClaim/claim",
+		},
+		{
+			name:      "recycle returns the EIP",
+			policy:    eipv1alpha1.EIPReclaimPolicyRecycle,
+			claimedBy: "EIPClaim/claim",
+		},
+		{
+			name:        "delete removes the EIP",
+			policy:      eipv1alpha1.EIPReclaimPolicyDelete,
+			claimedBy:   "EIPClaim/claim",
+			wantDeleted: true,
+		},
+		{
+			name:        "EIP claimed by someone else is left alone",
+			policy:      eipv1alpha1.EIPReclaimPolicyDelete,
+			claimedBy:   "Pod/web-0",
+			wantClaimed: "Pod/web-0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			claim, eip := boundClaim(tt.policy, tt.claimedBy)
+			c := newFakeClient(claim, eip)
+			r := newClaimReconciler(c)
+
+			if err := c.Delete(ctx, claim); err != nil {
+				t.Fatal(err)
+			}
+			if err := reconcileN(ctx, r, claim, 1); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			if err := c.Get(ctx, client.ObjectKeyFromObject(claim), claim); !errors.IsNotFound(err) {
+				t.Errorf("Get(claim) error = %v, want the claim gone", err)
+			}
+			err := c.Get(ctx, client.ObjectKeyFromObject(eip), eip)
+			if tt.wantDeleted {
+				if !errors.IsNotFound(err) {
+					t.Errorf("Get(eip) error = %v, want the EIP deleted", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := eip.Annotations[eipv1alpha1.AnnotationClaimedBy]; got != tt.wantClaimed {
+				t.Errorf("claimed by %q, want %q", got, tt.wantClaimed)
+			}
+		})
+	}
+}
+
+func TestEIPClaimLost(t *testing.T) {
+	ctx := context.Background()
+	claim, eip := boundClaim(eipv1alpha1.EIPReclaimPolicyRecycle, "Pod/web-0")
+	c := newFakeClient(claim, eip)
+	r := newClaimReconciler(c)
+
+	if err := reconcileN(ctx, r, claim, 1); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(claim), claim); err != nil {
+		t.Fatal(err)
+	}
+	if claim.Status.Phase != eipv1alpha1.EIPClaimLost {
+		t.Errorf("phase = %s, want %s", claim.Status.Phase, eipv1alpha1.EIPClaimLost)
+	}
+}
+
+func TestEIPClaimRequeuesWhenTakeBackLosesRace(t *testing.T) {
+	ctx := context.Background()
+	claim, eip := boundClaim(eipv1alpha1.EIPReclaimPolicyRecycle, "")
+	// Another consumer updates the EIP between our read and the claim marker update
+	c := fakeClientBuilder(claim, eip).WithInterceptorFuncs(interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if _, ok := obj.(*eipv1alpha1.EIP); ok {
+				return errors.NewConflict(schema.GroupResource{Group: eipv1alpha1.GroupVersion.Group, Resource: "eips"}, obj.GetName(), nil)
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+	r := newClaimReconciler(c)
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if !result.Requeue {
+		t.Errorf("Reconcile() = %+v, want a requeue", result)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(claim), claim); err != nil {
+		t.Fatal(err)
+	}
+	if claim.Status.Phase == eipv1alpha1.EIPClaimBound {
+		t.Errorf("phase = %s, want the claim not bound", claim.Status.Phase)
+	}
+}
@@ -0,0 +1,366 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+const (
+	eipPoolFinalizer = "eip.alibabacloud.com/pool-finalizer"
+
+	// Reasons
+	reasonPoolFilling        = "Filling"
+	reasonPoolFilled         = "Filled"
+	reasonPoolMaxSizeReached = "MaxSizeReached"
+)
+
+// poolMemberState 池成员EIP的分配状态
+type poolMemberState int
+
+const (
+	poolMemberPending poolMemberState = iota
+	poolMemberAvailable
+	poolMemberClaimed
+)
+
+// EIPPoolReconciler reconciles a EIPPool object
+type EIPPoolReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Record record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eippools,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eippools/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eippools/finalizers,verbs=update
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eips,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop
+func (r *EIPPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	pool := &eipv1alpha1.EIPPool{}
+	err := r.Get(ctx, req.NamespacedName, pool)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Check if the EIPPool instance is marked to be deleted
+	if !pool.ObjectMeta.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(pool, eipPoolFinalizer) {
+			if err := r.finalizePool(ctx, pool); err != nil {
+				return ctrl.Result{}, err
+			}
+
+			controllerutil.RemoveFinalizer(pool, eipPoolFinalizer)
+			if err := r.Update(ctx, pool); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer if not present
+	if !controllerutil.ContainsFinalizer(pool, eipPoolFinalizer) {
+		controllerutil.AddFinalizer(pool, eipPoolFinalizer)
+		if err := r.Update(ctx, pool); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.reconcilePool(ctx, pool); err != nil {
+		l.Error(err, "failed to reconcile EIPPool")
+		r.Record.Eventf(pool, "Warning", "ReconcileFailed", "Failed to reconcile EIPPool: %v", err)
+		r.setCondition(pool, conditionTypeReady, metav1.ConditionFalse, reasonSyncFailed, err.Error())
+		_ = r.updateStatus(ctx, pool)
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcilePool keeps MinAvailable idle EIPs in the pool without exceeding MaxSize
+func (r *EIPPoolReconciler) reconcilePool(ctx context.Context, pool *eipv1alpha1.EIPPool) error {
+	l := log.FromContext(ctx)
+
+	if pool.Spec.MaxSize < pool.Spec.MinAvailable {
+		r.setCondition(pool, conditionTypeReady, metav1.ConditionFalse, reasonInvalidSpec,
+			fmt.Sprintf("maxSize %d is less than minAvailable %d", pool.Spec.MaxSize, pool.Spec.MinAvailable))
+		return r.updateStatus(ctx, pool)
+	}
+
+	members, err := r.listMembers(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	var available, claimed, pending []*eipv1alpha1.EIP
+	for i := range members {
+		switch getPoolMemberState(&members[i]) {
+		case poolMemberAvailable:
+			available = append(available, &members[i])
+		case poolMemberClaimed:
+			claimed = append(claimed, &members[i])
+		default:
+			pending = append(pending, &members[i])
+		}
+	}
+	total := int32(len(members))
+
+	// Shrink idle members when the pool is larger than MaxSize, never touch claimed ones
+	if excess := total - pool.Spec.MaxSize; excess > 0 {
+		idle := make([]*eipv1alpha1.EIP, 0, len(pending)+len(available))
+		idle = append(append(idle, pending...), available...)
+		for i := 0; i < len(idle) && int32(i) < excess; i++ {
+			l.Info("deleting idle EIP from pool", "eip", idle[i].Name)
+			// A claim landing after the list changes the resourceVersion, the precondition keeps the member
+			err := r.Delete(ctx, idle[i], client.Preconditions{ResourceVersion: &idle[i].ResourceVersion})
+			if errors.IsConflict(err) {
+				l.Info("idle EIP changed before deletion, keeping it", "eip", idle[i].Name)
+				continue
+			}
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			r.Record.Eventf(pool, "Normal", "Deleted", "Deleted idle pool member EIP %s", idle[i].Name)
+		}
+		// The deletions requeue the pool through the owned EIP watch
+		return nil
+	}
+
+	// Refill the pool
+	want := pool.Spec.MinAvailable - int32(len(available)) - int32(len(pending))
+	if room := pool.Spec.MaxSize - total; want > room {
+		want = room
+	}
+	var names []string
+	if want > 0 {
+		if names, err = r.nextMemberNames(ctx, pool, want); err != nil {
+			return err
+		}
+	}
+	for _, name := range names {
+		eip := r.newMember(pool, name)
+		if err := controllerutil.SetControllerReference(pool, eip, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, eip); err != nil {
+			if errors.IsAlreadyExists(err) {
+				// 缓存还没看到上一轮创建的成员，跳过，等EIP事件触发下一轮reconcile
+				l.V(1).Info("pool member already exists", "eip", name)
+				continue
+			}
+			return fmt.Errorf("failed to create pool member: %w", err)
+		}
+		l.Info("created EIP for pool", "eip", eip.Name)
+		r.Record.Eventf(pool, "Normal", "Created", "Created pool member EIP %s", eip.Name)
+		pending = append(pending, eip)
+		total++
+	}
+
+	pool.Status.Available = int32(len(available))
+	pool.Status.Claimed = int32(len(claimed))
+	pool.Status.Pending = int32(len(pending))
+	pool.Status.Total = total
+	now := metav1.Now()
+	pool.Status.LastSyncTime = &now
+
+	switch {
+	case pool.Status.Available >= pool.Spec.MinAvailable:
+		r.setCondition(pool, conditionTypeReady, metav1.ConditionTrue, reasonPoolFilled, "Pool has enough available EIPs")
+	case total >= pool.Spec.MaxSize && pool.Status.Pending == 0:
+		r.setCondition(pool, conditionTypeReady, metav1.ConditionFalse, reasonPoolMaxSizeReached,
+			fmt.Sprintf("Pool reached maxSize %d with %d available EIPs", pool.Spec.MaxSize, pool.Status.Available))
+	default:
+		r.setCondition(pool, conditionTypeReady, metav1.ConditionFalse, reasonPoolFilling,
+			fmt.Sprintf("Waiting for %d pending EIPs", pool.Status.Pending))
+	}
+
+	return r.updateStatus(ctx, pool)
+}
+
+// listMembers returns the EIPs controlled by the pool, sorted from newest to oldest
+func (r *EIPPoolReconciler) listMembers(ctx context.Context, pool *eipv1alpha1.EIPPool) ([]eipv1alpha1.EIP, error) {
+	eips := &eipv1alpha1.EIPList{}
+	if err := r.List(ctx, eips, client.InNamespace(pool.Namespace),
+		client.MatchingLabels{eipv1alpha1.LabelEIPPool: pool.Name}); err != nil {
+		return nil, err
+	}
+
+	members := make([]eipv1alpha1.EIP, 0, len(eips.Items))
+	for _, eip := range eips.Items {
+		if !eip.DeletionTimestamp.IsZero() || !metav1.IsControlledBy(&eip, pool) {
+			continue
+		}
+		members = append(members, eip)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[j].CreationTimestamp.Before(&members[i].CreationTimestamp)
+	})
+	return members, nil
+}
+
+// nextMemberNames returns the lowest n free member names of the form <pool>-<index>.
+// 成员名是确定的，缓存过期时重复的Create会返回AlreadyExists，而不是多建一个EIP
+func (r *EIPPoolReconciler) nextMemberNames(ctx context.Context, pool *eipv1alpha1.EIPPool, n int32) ([]string, error) {
+	eips := &eipv1alpha1.EIPList{}
+	if err := r.List(ctx, eips, client.InNamespace(pool.Namespace)); err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool, len(eips.Items))
+	for _, eip := range eips.Items {
+		used[eip.Name] = true
+	}
+
+	names := make([]string, 0, n)
+	for i := 0; int32(len(names)) < n; i++ {
+		if name := fmt.Sprintf("%s-%d", pool.Name, i); !used[name] {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// newMember builds a pool member EIP from the pool template
+func (r *EIPPoolReconciler) newMember(pool *eipv1alpha1.EIPPool, name string) *eipv1alpha1.EIP {
+	template := pool.Spec.Template.DeepCopy()
+
+	labels := template.Metadata.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[eipv1alpha1.LabelEIPPool] = pool.Name
+
+	eip := &eipv1alpha1.EIP{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   pool.Namespace,
+			Labels:      labels,
+			Annotations: template.Metadata.Annotations,
+		},
+		Spec: template.Spec,
+	}
+	eip.Spec.AllocationID = ""
+	return eip
+}
+
+// finalizePool detaches claimed members so they survive the pool, idle members are garbage collected
+func (r *EIPPoolReconciler) finalizePool(ctx context.Context, pool *eipv1alpha1.EIPPool) error {
+	l := log.FromContext(ctx)
+
+	members, err := r.listMembers(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	for i := range members {
+		eip := &members[i]
+		if getPoolMemberState(eip) != poolMemberClaimed {
+			continue
+		}
+
+		l.Info("orphaning claimed EIP", "eip", eip.Name)
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			latest := &eipv1alpha1.EIP{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(eip), latest); err != nil {
+				return err
+			}
+			refs := latest.GetOwnerReferences()[:0]
+			for _, ref := range latest.GetOwnerReferences() {
+				if ref.UID != pool.UID {
+					refs = append(refs, ref)
+				}
+			}
+			latest.SetOwnerReferences(refs)
+			delete(latest.Labels, eipv1alpha1.LabelEIPPool)
+			return r.Update(ctx, latest)
+		})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		r.Record.Eventf(pool, "Normal", "Orphaned", "Kept claimed EIP %s after pool deletion", eip.Name)
+	}
+
+	return nil
+}
+
+// getPoolMemberState classifies a pool member
+func getPoolMemberState(eip *eipv1alpha1.EIP) poolMemberState {
+	if eip.Annotations[eipv1alpha1.AnnotationClaimedBy] != "" {
+		return poolMemberClaimed
+	}
+	switch {
+	case eip.Status.AllocationID == "":
+		return poolMemberPending
+	case eip.Status.Status == aliyunclient.EIPStatusAvailable:
+		return poolMemberAvailable
+	case eip.Status.InstanceID != "":
+		// Bound outside of the pool, treat it as handed out
+		return poolMemberClaimed
+	}
+	return poolMemberPending
+}
+
+// setCondition sets a condition on the EIPPool
+func (r *EIPPoolReconciler) setCondition(pool *eipv1alpha1.EIPPool, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: pool.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	apimeta.SetStatusCondition(&pool.Status.Conditions, condition)
+}
+
+// updateStatus updates the EIPPool status
+func (r *EIPPoolReconciler) updateStatus(ctx context.Context, pool *eipv1alpha1.EIPPool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return r.Status().Update(ctx, pool)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *EIPPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&eipv1alpha1.EIPPool{}).
+		Owns(&eipv1alpha1.EIP{}).
+		Complete(r)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+func testPool(minAvailable, maxSize int32) *eipv1alpha1.EIPPool {
+	return &eipv1alpha1.EIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default", UID: "pool-uid", Finalizers: []string{eipPoolFinalizer}},
+		Spec:       eipv1alpha1.EIPPoolSpec{MinAvailable: minAvailable, MaxSize: maxSize},
+	}
+}
+
+// poolMember returns an allocated idle member of pool, claimedBy marks it as handed out
+func poolMember(pool *eipv1alpha1.EIPPool, name, claimedBy string) *eipv1alpha1.EIP {
+	controller := true
+	eip := &eipv1alpha1.EIP{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: pool.Namespace,
+			Labels:    map[string]string{eipv1alpha1.LabelEIPPool: pool.Name},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: eipv1alpha1.GroupVersion.String(), Kind: "EIPPool", Name: pool.Name, UID: pool.UID,
+				Controller: &controller, BlockOwnerDeletion: &controller,
+			}},
+		},
+		Status: eipv1alpha1.EIPStatus{AllocationID: "eip-" + name, Status: aliyunclient.EIPStatusAvailable},
+	}
+	if claimedBy != "" {
+		eip.Annotations = map[string]string{eipv1alpha1.AnnotationClaimedBy: claimedBy}
+	}
+	return eip
+}
+
+func newPoolReconciler(c client.Client) *EIPPoolReconciler {
+	return &EIPPoolReconciler{Client: c, Scheme: c.Scheme(), Record: record.NewFakeRecorder(100)}
+}
+
+func TestEIPPoolRefill(t *testing.T) {
+	ctx := context.Background()
+	pool := testPool(2, 3)
+	c := newFakeClient(pool)
+	r := newPoolReconciler(c)
+
+	if err := reconcileN(ctx, r, pool, 1); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	for _, name := range []string{"pool-0", "pool-1"} {
+		eip := &eipv1alpha1.EIP{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, eip); err != nil {
+			t.Fatalf("Get(%s) error = %v", name, err)
+		}
+		if !metav1.IsControlledBy(eip, pool) || eip.Labels[eipv1alpha1.LabelEIPPool] != "pool" {
+			t.Errorf("%s is not a member of the pool: %+v", name, eip.ObjectMeta)
+		}
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pool), pool); err != nil {
+		t.Fatal(err)
+	}
+	if pool.Status.Pending != 2 || pool.Status.Total != 2 {
+		t.Errorf("status = %+v, want 2 pending of 2", pool.Status)
+	}
+}
+
+func TestEIPPoolShrinkKeepsClaimed(t *testing.T) {
+	ctx := context.Background()
+	pool := testPool(0, 1)
+	c := newFakeClient(pool,
+		poolMember(pool, "pool-0", "default/claim"),
+		poolMember(pool, "pool-1", ""),
+		poolMember(pool, "pool-2", ""))
+	r := newPoolReconciler(c)
+
+	if err := reconcileN(ctx, r, pool, 1); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	eips := &eipv1alpha1.EIPList{}
+	if err := c.List(ctx, eips); err != nil {
+		t.Fatal(err)
+	}
+	if len(eips.Items) != 1 || eips.Items[0].Name != "pool-0" {
+		t.Errorf("members left = %v, want only the claimed pool-0", eips.Items)
+	}
+}
+
+func TestEIPPoolShrinkSkipsMemberClaimedAfterListing(t *testing.T) {
+	ctx := context.Background()
+	pool := testPool(0, 0)
+	member := poolMember(pool, "pool-0", "")
+	// The claim controller wins the race: the member is claimed between the list and the delete
+	c := fakeClientBuilder(pool, member).WithInterceptorFuncs(interceptor.Funcs{
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			latest := &eipv1alpha1.EIP{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), latest); err != nil {
+				return err
+			}
+			latest.Annotations = map[string]string{eipv1alpha1.AnnotationClaimedBy: "default/claim"}
+			if err := c.Update(ctx, latest); err != nil {
+				return err
+			}
+			return c.Delete(ctx, obj, opts...)
+		},
+	}).Build()
+	r := newPoolReconciler(c)
+
+	if err := reconcileN(ctx, r, pool, 1); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(member), member); err != nil {
+		t.Errorf("Get() error = %v, want the claimed member kept", err)
+	}
+}
+
+func TestEIPPoolFinalizerOrphansClaimed(t *testing.T) {
+	ctx := context.Background()
+	pool := testPool(0, 2)
+	claimed := poolMember(pool, "pool-0", "default/claim")
+	c := newFakeClient(pool, claimed, poolMember(pool, "pool-1", ""))
+	r := newPoolReconciler(c)
+
+	if err := c.Delete(ctx, pool); err != nil {
+		t.Fatal(err)
+	}
+	if err := reconcileN(ctx, r, pool, 1); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(claimed), claimed); err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed.OwnerReferences) != 0 || claimed.Labels[eipv1alpha1.LabelEIPPool] != "" {
+		t.Errorf("claimed member still belongs to the pool: %+v", claimed.ObjectMeta)
+	}
+	idle := &eipv1alpha1.EIP{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "pool-1"}, idle); err != nil {
+		t.Fatal(err)
+	}
+	if !metav1.IsControlledBy(idle, pool) {
+		t.Errorf("idle member lost its owner, garbage collection would keep it")
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pool), pool); !errors.IsNotFound(err) {
+		t.Errorf("Get() error = %v, want the pool gone", err)
+	}
+}
@@ -0,0 +1,433 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+const (
+	eipSegmentFinalizer = "eip.alibabacloud.com/segment-finalizer"
+
+	// Reasons
+	reasonAllocating = "Allocating"
+	reasonAllocated  = "Allocated"
+)
+
+const (
+	// 地址段为异步分配，使用较短的轮询间隔
+	eipSegmentRequeueAfterPending = 10 * time.Second
+)
+
+// EIPSegmentReconciler reconciles a EIPSegment object
+type EIPSegmentReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Record   record.EventRecorder
+	Accounts *CloudAccountClients
+}
+
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eipsegments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eipsegments/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eipsegments/finalizers,verbs=update
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eips,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop
+func (r *EIPSegmentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	segment := &eipv1alpha1.EIPSegment{}
+	err := r.Get(ctx, req.NamespacedName, segment)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	var clients aliyunclient.RegionalAPI
+	account, err := r.Accounts.accountFor(ctx, segment.Namespace, segment.Spec.CloudAccountName, segment.Status.CloudAccountName, segment.Status.SegmentInstanceID != "")
+	if err == nil {
+		segment.Status.CloudAccountName = account
+		clients, err = r.Accounts.ForAccount(account)
+	}
+	if err != nil {
+		// Nothing was created in the cloud yet, otherwise deletion also waits for the account
+		if !segment.DeletionTimestamp.IsZero() && segment.Status.SegmentInstanceID == "" {
+			controllerutil.RemoveFinalizer(segment, eipSegmentFinalizer)
+			return ctrl.Result{}, r.Update(ctx, segment)
+		}
+		l.Info("cloud account is not ready", "cloudAccount", account, "error", err.Error())
+		r.setCondition(segment, conditionTypeReady, metav1.ConditionFalse, reasonCloudAccountNotReady, err.Error())
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, segment)
+	}
+	segment.Status.RegionID = resourceRegion(segment.Spec.RegionID, segment.Status.RegionID, clients)
+	api, err := clients.ForRegion(segment.Status.RegionID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Check if the EIPSegment instance is marked to be deleted
+	if !segment.ObjectMeta.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(segment, eipSegmentFinalizer) {
+			done, err := r.finalizeSegment(ctx, api, segment)
+			if err != nil {
+				if isThrottlingError(err) {
+					return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterThrottle}, nil
+				}
+				return ctrl.Result{}, err
+			}
+			if !done {
+				return ctrl.Result{RequeueAfter: eipSegmentRequeueAfterPending}, nil
+			}
+
+			controllerutil.RemoveFinalizer(segment, eipSegmentFinalizer)
+			if err := r.Update(ctx, segment); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer if not present
+	if !controllerutil.ContainsFinalizer(segment, eipSegmentFinalizer) {
+		controllerutil.AddFinalizer(segment, eipSegmentFinalizer)
+		if err := r.Update(ctx, segment); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	result, err := r.reconcileSegment(ctx, api, segment)
+	if err != nil {
+		if isThrottlingError(err) {
+			l.Info("API throttled, will retry later")
+			r.setCondition(segment, conditionTypeReady, metav1.ConditionFalse, reasonThrottled, "API throttled, retrying later")
+			_ = r.updateStatus(ctx, segment)
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterThrottle}, nil
+		}
+		l.Error(err, "failed to reconcile EIPSegment")
+		r.Record.Eventf(segment, "Warning", "ReconcileFailed", "Failed to reconcile EIPSegment: %v", err)
+		r.setCondition(segment, conditionTypeReady, metav1.ConditionFalse, reasonSyncFailed, err.Error())
+		_ = r.updateStatus(ctx, segment)
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
+	}
+
+	return result, nil
+}
+
+// reconcileSegment allocates the segment, reports its members and keeps the member EIPs in sync
+func (r *EIPSegmentReconciler) reconcileSegment(ctx context.Context, api aliyunclient.API, segment *eipv1alpha1.EIPSegment) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	// If SegmentInstanceID is not set, allocate a new segment
+	if segment.Spec.SegmentInstanceID == "" {
+		if segment.Status.SegmentInstanceID == "" {
+			l.Info("allocating new EIP segment", "mask", segment.Spec.EIPMask)
+			r.setCondition(segment, conditionTypeProgressing, metav1.ConditionTrue, reasonAllocating, "Allocating new EIP segment")
+			if err := r.updateStatus(ctx, segment); err != nil {
+				return ctrl.Result{}, err
+			}
+
+			// 地址段不支持标签，无法按归属标签找回，只能依靠ClientToken：
+			// 在写入SegmentInstanceID前重启时，重试的请求会返回同一个地址段
+			segmentID, err := api.AllocateEipSegmentAddress(ctx, &aliyunclient.EIPSegmentOptions{
+				EIPMask:            strconv.Itoa(int(segment.Spec.EIPMask)),
+				Bandwidth:          segment.Spec.Bandwidth,
+				InternetChargeType: segment.Spec.InternetChargeType,
+				ISP:                segment.Spec.ISP,
+				ResourceGroupID:    segment.Spec.ResourceGroupID,
+				ClientToken:        clientTokenFor(segment),
+			})
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+
+			segment.Status.SegmentInstanceID = segmentID
+			segment.Status.Provenance = eipv1alpha1.EIPProvenanceCreated
+			if err := r.updateStatus(ctx, segment); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.Record.Eventf(segment, "Normal", "Created", "Allocated EIP segment: %s", segmentID)
+		}
+
+		segment.Spec.SegmentInstanceID = segment.Status.SegmentInstanceID
+		if err := r.Update(ctx, segment); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if segment.Status.SegmentInstanceID == "" && segment.Status.Provenance == "" {
+		// The segment was referenced by ID before this object ever allocated one
+		segment.Status.Provenance = eipv1alpha1.EIPProvenanceImported
+	}
+
+	info, err := r.describeSegment(ctx, api, segment.Spec.SegmentInstanceID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if info == nil {
+		r.setCondition(segment, conditionTypeReady, metav1.ConditionFalse, reasonSyncFailed,
+			fmt.Sprintf("EIP segment %s not found", segment.Spec.SegmentInstanceID))
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, segment)
+	}
+
+	segment.Status.SegmentInstanceID = info.SegmentInstanceID
+	segment.Status.Segment = info.Segment
+	segment.Status.Status = info.Status
+	if info.Status != aliyunclient.EIPSegmentStatusAllocated {
+		r.setCondition(segment, conditionTypeReady, metav1.ConditionFalse, reasonAllocating,
+			fmt.Sprintf("EIP segment is %s", info.Status))
+		return ctrl.Result{RequeueAfter: eipSegmentRequeueAfterPending}, r.updateStatus(ctx, segment)
+	}
+
+	addrs, err := api.DescribeEipAddressesBySegment(ctx, segment.Spec.SegmentInstanceID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i].IPAddress < addrs[j].IPAddress })
+
+	eipNames, err := r.reconcileMemberEIPs(ctx, segment, addrs)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	members := make([]eipv1alpha1.EIPSegmentMember, 0, len(addrs))
+	for _, addr := range addrs {
+		members = append(members, eipv1alpha1.EIPSegmentMember{
+			AllocationID: addr.AllocationID,
+			EIPAddress:   addr.IPAddress,
+			Status:       addr.Status,
+			EIPName:      eipNames[addr.AllocationID],
+		})
+	}
+	segment.Status.Members = members
+
+	now := metav1.Now()
+	segment.Status.LastSyncTime = &now
+
+	r.setCondition(segment, conditionTypeProgressing, metav1.ConditionFalse, reasonAllocated, "EIP segment allocated")
+	r.setCondition(segment, conditionTypeReady, metav1.ConditionTrue, "Available", "EIP segment is ready")
+	r.setCondition(segment, conditionTypeSynced, metav1.ConditionTrue, "Synced", "EIP segment synced successfully")
+	if err := r.updateStatus(ctx, segment); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+// reconcileMemberEIPs creates one EIP per segment address when eipTemplate is set and removes the rest.
+// It returns the EIP name of each materialized member keyed by allocation ID.
+func (r *EIPSegmentReconciler) reconcileMemberEIPs(ctx context.Context, segment *eipv1alpha1.EIPSegment, addrs []aliyunclient.EIPAddress) (map[string]string, error) {
+	l := log.FromContext(ctx)
+
+	existing, err := r.listMemberEIPs(ctx, segment)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(addrs))
+	if segment.Spec.EIPTemplate != nil {
+		for _, addr := range addrs {
+			wanted[addr.AllocationID] = true
+		}
+	}
+
+	eipNames := make(map[string]string, len(existing))
+	for i := range existing {
+		eip := &existing[i]
+		if wanted[eip.Spec.AllocationID] {
+			eipNames[eip.Spec.AllocationID] = eip.Name
+			continue
+		}
+		if !eip.DeletionTimestamp.IsZero() {
+			continue
+		}
+		l.Info("deleting segment member EIP", "eip", eip.Name)
+		if err := r.Delete(ctx, eip); err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	for _, addr := range addrs {
+		if !wanted[addr.AllocationID] || eipNames[addr.AllocationID] != "" {
+			continue
+		}
+
+		eip := r.newMemberEIP(segment, addr)
+		if err := controllerutil.SetControllerReference(segment, eip, r.Scheme); err != nil {
+			return nil, err
+		}
+		l.Info("creating segment member EIP", "eip", eip.Name, "allocationID", addr.AllocationID)
+		if err := r.Create(ctx, eip); err != nil && !errors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		eipNames[addr.AllocationID] = eip.Name
+	}
+
+	return eipNames, nil
+}
+
+// newMemberEIP builds the EIP importing one address of the segment
+func (r *EIPSegmentReconciler) newMemberEIP(segment *eipv1alpha1.EIPSegment, addr aliyunclient.EIPAddress) *eipv1alpha1.EIP {
+	template := segment.Spec.EIPTemplate.DeepCopy()
+
+	labels := template.Metadata.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[eipv1alpha1.LabelEIPSegment] = segment.Name
+
+	eip := &eipv1alpha1.EIP{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        segment.Name + "-" + strings.ReplaceAll(addr.IPAddress, ".", "-"),
+			Namespace:   segment.Namespace,
+			Labels:      labels,
+			Annotations: template.Metadata.Annotations,
+		},
+		Spec: template.Spec,
+	}
+	eip.Spec.AllocationID = addr.AllocationID
+	// Member addresses live in the region and account of the segment
+	eip.Spec.RegionID = segment.Status.RegionID
+	eip.Spec.CloudAccountName = segment.Status.CloudAccountName
+	// Segment addresses are released together with the segment
+	eip.Spec.ReleaseStrategy = eipv1alpha1.ReleaseStrategyNever
+	return eip
+}
+
+// listMemberEIPs returns the EIPs materialized for the segment
+func (r *EIPSegmentReconciler) listMemberEIPs(ctx context.Context, segment *eipv1alpha1.EIPSegment) ([]eipv1alpha1.EIP, error) {
+	eips := &eipv1alpha1.EIPList{}
+	if err := r.List(ctx, eips, client.InNamespace(segment.Namespace),
+		client.MatchingLabels{eipv1alpha1.LabelEIPSegment: segment.Name}); err != nil {
+		return nil, err
+	}
+
+	members := make([]eipv1alpha1.EIP, 0, len(eips.Items))
+	for _, eip := range eips.Items {
+		if metav1.IsControlledBy(&eip, segment) {
+			members = append(members, eip)
+		}
+	}
+	return members, nil
+}
+
+// describeSegment returns the segment, nil if it does not exist
+func (r *EIPSegmentReconciler) describeSegment(ctx context.Context, api aliyunclient.API, segmentID string) (*aliyunclient.EIPSegment, error) {
+	segments, err := api.DescribeEipSegment(ctx, segmentID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range segments {
+		if segments[i].SegmentInstanceID == segmentID {
+			return &segments[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// finalizeSegment removes the member EIPs, then releases the segment according to ReleaseStrategy.
+// It returns false while member EIPs are still being deleted.
+func (r *EIPSegmentReconciler) finalizeSegment(ctx context.Context, api aliyunclient.API, segment *eipv1alpha1.EIPSegment) (bool, error) {
+	l := log.FromContext(ctx)
+
+	members, err := r.listMemberEIPs(ctx, segment)
+	if err != nil {
+		return false, err
+	}
+	if len(members) > 0 {
+		for i := range members {
+			if !members[i].DeletionTimestamp.IsZero() {
+				continue
+			}
+			if err := r.Delete(ctx, &members[i]); err != nil && !errors.IsNotFound(err) {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	segmentID := segment.Status.SegmentInstanceID
+	if !releasedOnDelete(segment.Spec.ReleaseStrategy, segment.Status.Provenance) || segmentID == "" {
+		l.Info("skipping EIP segment release", "releaseStrategy", segment.Spec.ReleaseStrategy, "provenance", segment.Status.Provenance)
+		r.Record.Event(segment, "Normal", "Skipped", "Skipped EIP segment release due to ReleaseStrategy")
+		return true, nil
+	}
+
+	info, err := r.describeSegment(ctx, api, segmentID)
+	if err != nil {
+		return false, err
+	}
+	if info == nil {
+		l.Info("EIP segment not found, assuming already released", "segmentID", segmentID)
+		return true, nil
+	}
+
+	l.Info("releasing EIP segment", "segmentID", segmentID)
+	if err := api.ReleaseEipSegmentAddress(ctx, segmentID); err != nil {
+		r.Record.Eventf(segment, "Warning", "ReleaseFailed", "Failed to release EIP segment: %v", err)
+		return false, err
+	}
+	r.Record.Eventf(segment, "Normal", "Released", "Released EIP segment: %s", segmentID)
+	return true, nil
+}
+
+// setCondition sets a condition on the EIPSegment
+func (r *EIPSegmentReconciler) setCondition(segment *eipv1alpha1.EIPSegment, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: segment.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	apimeta.SetStatusCondition(&segment.Status.Conditions, condition)
+}
+
+// updateStatus updates the EIPSegment status
+func (r *EIPSegmentReconciler) updateStatus(ctx context.Context, segment *eipv1alpha1.EIPSegment) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return r.Status().Update(ctx, segment)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *EIPSegmentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&eipv1alpha1.EIPSegment{}).
+		Owns(&eipv1alpha1.EIP{}).
+		Complete(r)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+func TestEIPSegmentLifecycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy eipv1alpha1.ReleaseStrategy
+		// importID references an existing segment
+		importID string
+
+		wantAllocated  bool
+		wantProvenance eipv1alpha1.EIPProvenance
+		wantReleased   bool
+	}{
+		{
+			name:           "allocated segment is released by default",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDeleteIfCreated,
+			wantAllocated:  true,
+			wantProvenance: eipv1alpha1.EIPProvenanceCreated,
+			wantReleased:   true,
+		},
+		{
+			name:           "imported segment is kept by default",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDeleteIfCreated,
+			importID:       "eipsg-imported",
+			wantProvenance: eipv1alpha1.EIPProvenanceImported,
+		},
+		{
+			name:           "imported segment is released on OnDelete",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDelete,
+			importID:       "eipsg-imported",
+			wantProvenance: eipv1alpha1.EIPProvenanceImported,
+			wantReleased:   true,
+		},
+		{
+			name:           "allocated segment is kept on Never",
+			strategy:       eipv1alpha1.ReleaseStrategyNever,
+			wantAllocated:  true,
+			wantProvenance: eipv1alpha1.EIPProvenanceCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := newFakeCloud()
+			if tt.importID != "" {
+				cloud.addSegment(tt.importID, 2)
+			}
+			segment := &eipv1alpha1.EIPSegment{
+				ObjectMeta: metav1.ObjectMeta{Name: "seg", Namespace: "default", UID: "seg-uid"},
+				Spec: eipv1alpha1.EIPSegmentSpec{
+					SegmentInstanceID: tt.importID,
+					EIPMask:           28,
+					EIPTemplate:       &eipv1alpha1.EIPTemplateSpec{},
+					ReleaseStrategy:   tt.strategy,
+				},
+			}
+
+			c := newFakeClient(testNamespace(), segment)
+			r := &EIPSegmentReconciler{
+				Client:   c,
+				Scheme:   c.Scheme(),
+				Record:   record.NewFakeRecorder(100),
+				Accounts: accountsFor(c, cloud),
+			}
+
+			if err := reconcileN(ctx, r, segment, 1); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(segment), segment); err != nil {
+				t.Fatal(err)
+			}
+			if got := cloud.called("AllocateEipSegmentAddress") == 1; got != tt.wantAllocated {
+				t.Errorf("allocated = %v, want %v", got, tt.wantAllocated)
+			}
+			segmentID := segment.Status.SegmentInstanceID
+			if segmentID == "" || segment.Spec.SegmentInstanceID != segmentID {
+				t.Errorf("spec ID %q, status ID %q, want both set", segment.Spec.SegmentInstanceID, segmentID)
+			}
+			if segment.Status.Provenance != tt.wantProvenance {
+				t.Errorf("provenance = %s, want %s", segment.Status.Provenance, tt.wantProvenance)
+			}
+
+			eips := &eipv1alpha1.EIPList{}
+			if err := c.List(ctx, eips, client.MatchingLabels{eipv1alpha1.LabelEIPSegment: segment.Name}); err != nil {
+				t.Fatal(err)
+			}
+			if len(eips.Items) != 2 {
+				t.Fatalf("member EIPs = %d, want 2", len(eips.Items))
+			}
+			for _, eip := range eips.Items {
+				if eip.Spec.ReleaseStrategy != eipv1alpha1.ReleaseStrategyNever || !metav1.IsControlledBy(&eip, segment) {
+					t.Errorf("member %s = %+v, want controlled by the segment and never released on its own", eip.Name, eip.Spec)
+				}
+			}
+
+			// The first pass deletes the member EIPs, the second one releases the segment
+			if err := c.Delete(ctx, segment); err != nil {
+				t.Fatal(err)
+			}
+			if err := reconcileN(ctx, r, segment, 2); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if err := c.List(ctx, eips, client.MatchingLabels{eipv1alpha1.LabelEIPSegment: segment.Name}); err != nil {
+				t.Fatal(err)
+			}
+			if len(eips.Items) != 0 {
+				t.Errorf("member EIPs left = %d, want 0", len(eips.Items))
+			}
+			if got := cloud.called("ReleaseEipSegmentAddress "+segmentID) == 1; got != tt.wantReleased {
+				t.Errorf("released = %v, want %v", got, tt.wantReleased)
+			}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(segment), segment); !errors.IsNotFound(err) {
+				t.Errorf("Get() error = %v, want the segment gone", err)
+			}
+		})
+	}
+}
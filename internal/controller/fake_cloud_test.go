@@ -0,0 +1,360 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+const testRegionID = "cn-hangzhou"
+
+// fakeCloud keeps cloud resources in memory for the controller tests.
+// Calls no test drives fall through to the nil API and panic.
+type fakeCloud struct {
+	aliyunclient.API
+
+	eips     map[string]*aliyunclient.EIPAddress
+	packages map[string]*aliyunclient.BandwidthPackage
+	// pools 按地址池ID保存CIDR地址段
+	pools map[string][]aliyunclient.PublicIPAddressPoolCidrBlock
+	// segments 地址段及其包含的EIP
+	segments       map[string]*aliyunclient.EIPSegment
+	segmentMembers map[string][]string
+	// tags 按资源ID保存标签
+	tags   map[string]map[string]string
+	calls  []string
+	lastID int
+}
+
+func newFakeCloud() *fakeCloud {
+	return &fakeCloud{
+		eips:           map[string]*aliyunclient.EIPAddress{},
+		packages:       map[string]*aliyunclient.BandwidthPackage{},
+		pools:          map[string][]aliyunclient.PublicIPAddressPoolCidrBlock{},
+		segments:       map[string]*aliyunclient.EIPSegment{},
+		segmentMembers: map[string][]string{},
+		tags:           map[string]map[string]string{},
+	}
+}
+
+// record remembers a mutating call, the tests assert on what reached the cloud
+func (f *fakeCloud) record(call string, args ...string) {
+	f.calls = append(f.calls, strings.Join(append([]string{call}, args...), " "))
+}
+
+// called returns how many times a call was made
+func (f *fakeCloud) called(call string) int {
+	n := 0
+	for _, c := range f.calls {
+		if c == call || strings.HasPrefix(c, call+" ") {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeCloud) newID(prefix string) string {
+	f.lastID++
+	return fmt.Sprintf("%s-%d", prefix, f.lastID)
+}
+
+// tagged reports whether the resource carries all tags
+func (f *fakeCloud) tagged(resourceID string, tags map[string]string) bool {
+	for k, v := range tags {
+		if f.tags[resourceID][k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *fakeCloud) TagResources(ctx context.Context, resourceType string, resourceIDs []string, tags map[string]string) error {
+	for _, id := range resourceIDs {
+		if f.tags[id] == nil {
+			f.tags[id] = map[string]string{}
+		}
+		for k, v := range tags {
+			f.tags[id][k] = v
+		}
+	}
+	return nil
+}
+
+func (f *fakeCloud) UntagResources(ctx context.Context, resourceType string, resourceIDs []string, tagKeys []string) error {
+	for _, id := range resourceIDs {
+		f.record("UntagResources", append([]string{id}, tagKeys...)...)
+		for _, k := range tagKeys {
+			delete(f.tags[id], k)
+		}
+	}
+	return nil
+}
+
+func (f *fakeCloud) ListTagResources(ctx context.Context, resourceType string, resourceID string) (map[string]string, error) {
+	tags := make(map[string]string, len(f.tags[resourceID]))
+	for k, v := range f.tags[resourceID] {
+		tags[k] = v
+	}
+	return tags, nil
+}
+
+func (f *fakeCloud) DescribeEipAddresses(ctx context.Context, allocationID, eipAddress, associatedInstanceID, associatedInstanceType string) ([]aliyunclient.EIPAddress, error) {
+	eip, ok := f.eips[allocationID]
+	if !ok {
+		return nil, nil
+	}
+	return []aliyunclient.EIPAddress{*eip}, nil
+}
+
+func (f *fakeCloud) DescribeEipAddressesByTags(ctx context.Context, tags map[string]string) ([]aliyunclient.EIPAddress, error) {
+	var eips []aliyunclient.EIPAddress
+	for id, eip := range f.eips {
+		if f.tagged(id, tags) {
+			found := *eip
+			found.Tags = f.tags[id]
+			eips = append(eips, found)
+		}
+	}
+	return eips, nil
+}
+
+func (f *fakeCloud) ReleaseEIPAddress(ctx context.Context, eipID string) error {
+	f.record("ReleaseEIPAddress", eipID)
+	delete(f.eips, eipID)
+	return nil
+}
+
+func (f *fakeCloud) AssociateEipAddress(ctx context.Context, allocationID, instanceID, instanceType, privateIPAddress string) error {
+	f.record("AssociateEipAddress", allocationID, instanceID)
+	eip := f.eips[allocationID]
+	eip.Status = aliyunclient.EIPStatusInUse
+	eip.InstanceID = instanceID
+	eip.InstanceType = instanceType
+	eip.PrivateIPAddress = privateIPAddress
+	return nil
+}
+
+func (f *fakeCloud) UnassociateEipAddress(ctx context.Context, allocationID, instanceID, instanceType, privateIPAddress string) error {
+	f.record("UnassociateEipAddress", allocationID, instanceID)
+	eip := f.eips[allocationID]
+	eip.Status = aliyunclient.EIPStatusAvailable
+	eip.InstanceID = ""
+	eip.InstanceType = ""
+	eip.PrivateIPAddress = ""
+	return nil
+}
+
+func (f *fakeCloud) CreateCommonBandwidthPackage(ctx context.Context, opts *aliyunclient.BandwidthPackageOptions) (string, error) {
+	id := f.newID("cbwp")
+	f.record("CreateCommonBandwidthPackage", id)
+	f.packages[id] = &aliyunclient.BandwidthPackage{
+		BandwidthPackageID: id,
+		Status:             aliyunclient.BandwidthPackageStatusAvailable,
+		Bandwidth:          opts.Bandwidth,
+	}
+	return id, nil
+}
+
+func (f *fakeCloud) DescribeCommonBandwidthPackages(ctx context.Context, packageID string) ([]aliyunclient.BandwidthPackage, error) {
+	pkg, ok := f.packages[packageID]
+	if !ok {
+		return nil, nil
+	}
+	return []aliyunclient.BandwidthPackage{*pkg}, nil
+}
+
+func (f *fakeCloud) DescribeCommonBandwidthPackagesByTags(ctx context.Context, tags map[string]string) ([]aliyunclient.BandwidthPackage, error) {
+	var pkgs []aliyunclient.BandwidthPackage
+	for id, pkg := range f.packages {
+		if f.tagged(id, tags) {
+			pkgs = append(pkgs, *pkg)
+		}
+	}
+	return pkgs, nil
+}
+
+func (f *fakeCloud) DeleteCommonBandwidthPackage(ctx context.Context, packageID string) error {
+	f.record("DeleteCommonBandwidthPackage", packageID)
+	delete(f.packages, packageID)
+	return nil
+}
+
+func (f *fakeCloud) CreatePublicIpAddressPool(ctx context.Context, opts *aliyunclient.PublicIPAddressPoolOptions) (string, error) {
+	id := f.newID("pippool")
+	f.record("CreatePublicIpAddressPool", id)
+	f.pools[id] = nil
+	return id, nil
+}
+
+func (f *fakeCloud) ListPublicIpAddressPoolsByTags(ctx context.Context, tags map[string]string) ([]string, error) {
+	var ids []string
+	for id := range f.pools {
+		if f.tagged(id, tags) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (f *fakeCloud) DeletePublicIpAddressPool(ctx context.Context, poolID string) error {
+	f.record("DeletePublicIpAddressPool", poolID)
+	delete(f.pools, poolID)
+	return nil
+}
+
+func (f *fakeCloud) AddPublicIpAddressPoolCidrBlock(ctx context.Context, poolID, cidrBlock string, cidrMask int) error {
+	if cidrBlock == "" {
+		cidrBlock = fmt.Sprintf("47.0.%d.0/%d", len(f.pools[poolID]), cidrMask)
+	}
+	f.record("AddPublicIpAddressPoolCidrBlock", poolID, cidrBlock)
+	f.pools[poolID] = append(f.pools[poolID], aliyunclient.PublicIPAddressPoolCidrBlock{CidrBlock: cidrBlock, Status: "Available", TotalIPNum: 16})
+	return nil
+}
+
+func (f *fakeCloud) DeletePublicIpAddressPoolCidrBlock(ctx context.Context, poolID, cidrBlock string) error {
+	f.record("DeletePublicIpAddressPoolCidrBlock", poolID, cidrBlock)
+	blocks := f.pools[poolID][:0]
+	for _, block := range f.pools[poolID] {
+		if block.CidrBlock != cidrBlock {
+			blocks = append(blocks, block)
+		}
+	}
+	f.pools[poolID] = blocks
+	return nil
+}
+
+func (f *fakeCloud) ListPublicIpAddressPoolCidrBlocks(ctx context.Context, poolID string) ([]aliyunclient.PublicIPAddressPoolCidrBlock, error) {
+	blocks, ok := f.pools[poolID]
+	if !ok {
+		return nil, fmt.Errorf("ResourceNotFound.PublicIpAddressPool: %s", poolID)
+	}
+	return append([]aliyunclient.PublicIPAddressPoolCidrBlock(nil), blocks...), nil
+}
+
+// addSegment puts an allocated segment holding n EIPs into the cloud
+func (f *fakeCloud) addSegment(id string, n int) {
+	f.segments[id] = &aliyunclient.EIPSegment{SegmentInstanceID: id, Segment: "47.2.0.0/28", Status: aliyunclient.EIPSegmentStatusAllocated}
+	for i := 0; i < n; i++ {
+		allocationID := fmt.Sprintf("%s-eip-%d", id, i)
+		f.eips[allocationID] = &aliyunclient.EIPAddress{
+			AllocationID: allocationID, IPAddress: fmt.Sprintf("47.2.0.%d", i), Status: aliyunclient.EIPStatusAvailable,
+		}
+		f.segmentMembers[id] = append(f.segmentMembers[id], allocationID)
+	}
+}
+
+func (f *fakeCloud) AllocateEipSegmentAddress(ctx context.Context, opts *aliyunclient.EIPSegmentOptions) (string, error) {
+	id := f.newID("eipsg")
+	f.record("AllocateEipSegmentAddress", id)
+	f.addSegment(id, 2)
+	return id, nil
+}
+
+func (f *fakeCloud) DescribeEipSegment(ctx context.Context, segmentID string) ([]aliyunclient.EIPSegment, error) {
+	segment, ok := f.segments[segmentID]
+	if !ok {
+		return nil, nil
+	}
+	return []aliyunclient.EIPSegment{*segment}, nil
+}
+
+func (f *fakeCloud) DescribeEipAddressesBySegment(ctx context.Context, segmentID string) ([]aliyunclient.EIPAddress, error) {
+	var eips []aliyunclient.EIPAddress
+	for _, allocationID := range f.segmentMembers[segmentID] {
+		eips = append(eips, *f.eips[allocationID])
+	}
+	return eips, nil
+}
+
+func (f *fakeCloud) ReleaseEipSegmentAddress(ctx context.Context, segmentID string) error {
+	f.record("ReleaseEipSegmentAddress", segmentID)
+	for _, allocationID := range f.segmentMembers[segmentID] {
+		delete(f.eips, allocationID)
+	}
+	delete(f.segments, segmentID)
+	delete(f.segmentMembers, segmentID)
+	return nil
+}
+
+// accountsFor returns account clients whose default credentials talk to cloud in every region
+func accountsFor(c client.Reader, cloud aliyunclient.API) *CloudAccountClients {
+	return &CloudAccountClients{
+		Client: c,
+		Default: aliyunclient.NewClientCache(testRegionID, func(regionID string) (aliyunclient.API, error) {
+			return cloud, nil
+		}),
+	}
+}
+
+// newFakeClient returns a fake client holding objs, the status of the operator types is a subresource
+func newFakeClient(objs ...client.Object) client.Client {
+	return fakeClientBuilder(objs...).Build()
+}
+
+// fakeClientBuilder is newFakeClient for tests that also need field indexes
+func fakeClientBuilder(objs ...client.Object) *fake.ClientBuilder {
+	return fake.NewClientBuilder().
+		WithScheme(testScheme()).
+		WithObjects(objs...).
+		WithStatusSubresource(&eipv1alpha1.EIP{}, &eipv1alpha1.EIPAssociation{}, &eipv1alpha1.EIPPool{}, &eipv1alpha1.EIPClaim{},
+			&eipv1alpha1.BandwidthPackage{}, &eipv1alpha1.PublicIPAddressPool{}, &eipv1alpha1.EIPSegment{})
+}
+
+// testScheme knows the core types and the operator types
+func testScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	if err := eipv1alpha1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	return scheme
+}
+
+// reconcileN runs Reconcile n times, like the requeues of the manager would
+func reconcileN(ctx context.Context, r interface {
+	Reconcile(context.Context, ctrl.Request) (ctrl.Result, error)
+}, obj client.Object, n int) error {
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+	for i := 0; i < n; i++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// testNamespace is the namespace the namespaced test objects live in
+func testNamespace() *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+}
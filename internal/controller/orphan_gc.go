@@ -0,0 +1,226 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+	"github.com/chrisliu1995/alibabacloud-eip-operator/pkg/config"
+)
+
+const (
+	// orphanEventKind 孤儿EIP没有对应的CR，事件挂在该类型的对象引用上
+	orphanEventKind = "EIPAddress"
+)
+
+var (
+	orphanedEIPs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "alibabacloud_eip_operator_orphaned_eips",
+		Help: "Number of operator-created EIPs without a matching EIP object in the last scan",
+	})
+	orphanedEIPsReleased = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "alibabacloud_eip_operator_orphaned_eips_released_total",
+		Help: "Number of orphaned EIPs released by the orphan collector",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanedEIPs, orphanedEIPsReleased)
+}
+
+// OrphanCollector periodically looks for EIPs created by the operator in this cluster that no EIP object references any more,
+// e.g. after a finalizer was stripped. Orphans are reported and, when opted in, released after a grace period.
+type OrphanCollector struct {
+	Client client.Reader
+	Record record.EventRecorder
+	// Accounts 扫描控制器自身凭证和每个有效CloudAccount下的EIP
+	Accounts *CloudAccountClients
+	Config   config.OrphanGC
+	// ClusterID 只扫描本集群创建的EIP
+	ClusterID string
+
+	// firstSeen 记录孤儿EIP首次被发现的时间，重启后重新计算宽限期
+	firstSeen map[string]time.Time
+}
+
+var _ manager.LeaderElectionRunnable = &OrphanCollector{}
+
+// NeedLeaderElection only lets the leader release EIPs
+func (c *OrphanCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable
+func (c *OrphanCollector) Start(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("orphan-gc")
+	// 只凭managed-by标签会把共用账号的其他集群创建的EIP当作孤儿
+	if c.ClusterID == "" {
+		return fmt.Errorf("orphan collector requires a clusterID")
+	}
+	c.firstSeen = make(map[string]time.Time)
+
+	ticker := time.NewTicker(c.Config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := c.collect(log.IntoContext(ctx, l)); err != nil {
+			l.Error(err, "orphan scan failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect runs one scan
+func (c *OrphanCollector) collect(ctx context.Context) error {
+	l := log.FromContext(ctx)
+
+	eips := &eipv1alpha1.EIPList{}
+	if err := c.Client.List(ctx, eips); err != nil {
+		return err
+	}
+	accounts := c.Accounts.All()
+	referenced := make(map[string]bool, len(eips.Items))
+	owners := make(map[string]bool, len(eips.Items))
+	regions := make(map[string]map[string]bool, len(accounts))
+	for account, clients := range accounts {
+		regions[account] = map[string]bool{clients.DefaultRegion(): true}
+	}
+	for i := range eips.Items {
+		for _, id := range eips.Items[i].AllocationIDs() {
+			referenced[id] = true
+		}
+		owners[string(eips.Items[i].UID)] = true
+		account := eips.Items[i].Status.CloudAccountName
+		if accountRegions, ok := regions[account]; ok {
+			accountRegions[eipRegion(&eips.Items[i], accounts[account])] = true
+		}
+	}
+
+	// Scan every region an EIP object uses in each account, orphans of a region without objects left are found in the default region only
+	seen := make(map[string]bool)
+	for account, clients := range accounts {
+		for regionID := range regions[account] {
+			if err := c.collectRegion(ctx, clients, regionID, referenced, owners, seen); err != nil {
+				l.Error(err, "orphan scan failed", "cloudAccount", account, "region", regionID)
+			}
+		}
+	}
+
+	// Forget EIPs that were adopted or released since the last scan
+	for id := range c.firstSeen {
+		if !seen[id] {
+			delete(c.firstSeen, id)
+		}
+	}
+	orphanedEIPs.Set(float64(len(seen)))
+	return nil
+}
+
+// collectRegion scans one region, orphans found are added to seen
+func (c *OrphanCollector) collectRegion(ctx context.Context, clients aliyunclient.RegionalAPI, regionID string, referenced, owners, seen map[string]bool) error {
+	l := log.FromContext(ctx).WithValues("region", regionID)
+
+	api, err := clients.ForRegion(regionID)
+	if err != nil {
+		return err
+	}
+	addrs, err := api.DescribeEipAddressesByTags(ctx, operatorTags(c.ClusterID))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range addrs {
+		addr := &addrs[i]
+		// An object whose UID is on the EIP may still be persisting the ID after creation
+		if referenced[addr.AllocationID] || owners[addr.Tags[tagKeyOwnerUID]] {
+			continue
+		}
+		seen[addr.AllocationID] = true
+
+		first, ok := c.firstSeen[addr.AllocationID]
+		if !ok {
+			first = now
+			c.firstSeen[addr.AllocationID] = now
+			l.Info("found orphaned EIP", "allocationID", addr.AllocationID, "ipAddress", addr.IPAddress, "ownerUID", addr.Tags[tagKeyOwnerUID])
+			c.event(addr, corev1.EventTypeWarning, "OrphanDetected",
+				"EIP %s (%s) was created by the operator but no EIP object references it", addr.AllocationID, addr.IPAddress)
+		}
+		if now.Sub(first) < c.Config.GracePeriod {
+			continue
+		}
+		if !c.Config.Release && !c.Config.DryRun {
+			continue
+		}
+		if addr.Status != aliyunclient.EIPStatusAvailable || addr.DeletionProtection {
+			l.Info("orphaned EIP is in use or protected, not releasing", "allocationID", addr.AllocationID,
+				"status", addr.Status, "instanceID", addr.InstanceID, "deletionProtection", addr.DeletionProtection)
+			continue
+		}
+
+		if c.Config.DryRun {
+			l.Info("dry run: would release orphaned EIP", "allocationID", addr.AllocationID, "ipAddress", addr.IPAddress, "orphanedSince", first)
+			c.event(addr, corev1.EventTypeNormal, "OrphanWouldRelease",
+				"Dry run: EIP %s (%s) would be released, orphaned since %s", addr.AllocationID, addr.IPAddress, first.Format(time.RFC3339))
+			continue
+		}
+
+		l.Info("releasing orphaned EIP", "allocationID", addr.AllocationID, "ipAddress", addr.IPAddress, "orphanedSince", first)
+		if addr.BandwidthPackageID != "" {
+			if err := api.RemoveCommonBandwidthPackageIP(ctx, addr.AllocationID, addr.BandwidthPackageID); err != nil {
+				l.Error(err, "failed to remove orphaned EIP from bandwidth package", "allocationID", addr.AllocationID)
+				continue
+			}
+		}
+		if err := api.ReleaseEIPAddress(ctx, addr.AllocationID); err != nil && !isEIPNotFoundError(err) {
+			l.Error(err, "failed to release orphaned EIP", "allocationID", addr.AllocationID)
+			c.event(addr, corev1.EventTypeWarning, "ReleaseFailed", "Failed to release orphaned EIP %s: %v", addr.AllocationID, err)
+			continue
+		}
+		orphanedEIPsReleased.Inc()
+		delete(seen, addr.AllocationID)
+		c.event(addr, corev1.EventTypeNormal, "OrphanReleased", "Released orphaned EIP %s (%s)", addr.AllocationID, addr.IPAddress)
+	}
+	return nil
+}
+
+// event records an event for a cloud EIP that has no EIP object
+func (c *OrphanCollector) event(addr *aliyunclient.EIPAddress, eventType, reason, messageFmt string, args ...interface{}) {
+	ref := &corev1.ObjectReference{
+		APIVersion: eipv1alpha1.GroupVersion.String(),
+		Kind:       orphanEventKind,
+		Namespace:  c.Config.EventNamespace,
+		Name:       addr.AllocationID,
+	}
+	c.Record.Eventf(ref, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+	"github.com/chrisliu1995/alibabacloud-eip-operator/pkg/config"
+)
+
+func TestOrphanCollector(t *testing.T) {
+	tests := []struct {
+		name string
+		// orphanedFor is how long the EIP has been known as an orphan, 0 for a first sighting
+		orphanedFor time.Duration
+		release     bool
+		dryRun      bool
+		status      string
+		clusterID   string
+		// referenced and owned keep an EIP object pointing at the cloud EIP
+		referenced bool
+		owned      bool
+
+		wantOrphan   bool
+		wantReleased bool
+		wantEvent    string
+	}{
+		{
+			name:         "orphan past the grace period is released",
+			orphanedFor:  2 * time.Hour,
+			release:      true,
+			wantReleased: true,
+			wantEvent:    "OrphanReleased",
+		},
+		{
+			name:       "new orphan waits for the grace period",
+			release:    true,
+			wantOrphan: true,
+			wantEvent:  "OrphanDetected",
+		},
+		{
+			name:        "orphans are only reported by default",
+			orphanedFor: 2 * time.Hour,
+			wantOrphan:  true,
+		},
+		{
+			name:        "dry run reports the release",
+			orphanedFor: 2 * time.Hour,
+			release:     true,
+			dryRun:      true,
+			wantOrphan:  true,
+			wantEvent:   "OrphanWouldRelease",
+		},
+		{
+			name:        "bound orphan is kept",
+			orphanedFor: 2 * time.Hour,
+			release:     true,
+			status:      aliyunclient.EIPStatusInUse,
+			wantOrphan:  true,
+		},
+		{
+			name:        "EIP of another cluster is ignored",
+			orphanedFor: 2 * time.Hour,
+			release:     true,
+			clusterID:   "other",
+		},
+		{
+			name:        "referenced EIP is not an orphan",
+			orphanedFor: 2 * time.Hour,
+			release:     true,
+			referenced:  true,
+		},
+		{
+			name:        "EIP of an object still persisting its ID is not an orphan",
+			orphanedFor: 2 * time.Hour,
+			release:     true,
+			owned:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := newFakeCloud()
+			status := tt.status
+			if status == "" {
+				status = aliyunclient.EIPStatusAvailable
+			}
+			clusterID := tt.clusterID
+			if clusterID == "" {
+				clusterID = "cluster-1"
+			}
+			cloud.eips["eip-1"] = &aliyunclient.EIPAddress{AllocationID: "eip-1", IPAddress: "47.0.0.1", Status: status}
+			cloud.tags["eip-1"] = operatorTags(clusterID)
+			cloud.tags["eip-1"][tagKeyOwnerUID] = "web-uid"
+
+			var objs []client.Object
+			if tt.referenced || tt.owned {
+				eip := &eipv1alpha1.EIP{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "other-uid"}}
+				if tt.referenced {
+					eip.Status.AllocationID = "eip-1"
+				}
+				if tt.owned {
+					eip.UID = "web-uid"
+				}
+				objs = append(objs, eip)
+			}
+			c := newFakeClient(objs...)
+			recorder := record.NewFakeRecorder(100)
+			collector := &OrphanCollector{
+				Client:    c,
+				Record:    recorder,
+				Accounts:  accountsFor(c, cloud),
+				Config:    config.OrphanGC{GracePeriod: time.Hour, Release: tt.release, DryRun: tt.dryRun},
+				ClusterID: "cluster-1",
+				firstSeen: map[string]time.Time{},
+			}
+			if tt.orphanedFor > 0 {
+				collector.firstSeen["eip-1"] = time.Now().Add(-tt.orphanedFor)
+			}
+
+			if err := collector.collect(ctx); err != nil {
+				t.Fatalf("collect() error = %v", err)
+			}
+			if _, ok := collector.firstSeen["eip-1"]; ok != tt.wantOrphan {
+				t.Errorf("tracked as orphan = %v, want %v", ok, tt.wantOrphan)
+			}
+			if got := cloud.called("ReleaseEIPAddress eip-1") == 1; got != tt.wantReleased {
+				t.Errorf("released = %v, want %v", got, tt.wantReleased)
+			}
+			if tt.wantEvent != "" && !recorded(recorder, tt.wantEvent) {
+				t.Errorf("no %s event recorded", tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestOrphanCollectorRequiresClusterID(t *testing.T) {
+	collector := &OrphanCollector{Config: config.OrphanGC{Interval: time.Minute}}
+	if err := collector.Start(context.Background()); err == nil {
+		t.Errorf("Start() error = nil, want an error without a clusterID")
+	}
+}
+
+// recorded reports whether an event with reason was recorded
+func recorded(recorder *record.FakeRecorder, reason string) bool {
+	for {
+		select {
+		case event := <-recorder.Events:
+			if strings.Contains(event, " "+reason+" ") {
+				return true
+			}
+		default:
+			return false
+		}
+	}
+}
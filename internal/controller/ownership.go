@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+const (
+	// tagKeyOwnerUID 云上资源的归属标签，值为创建或导入该资源的CR UID
+	tagKeyOwnerUID = "eip.alibabacloud.com/owner-uid"
+	// tagKeyOwnerNamespace 归属CR所在的命名空间
+	tagKeyOwnerNamespace = "eip.alibabacloud.com/owner-namespace"
+	// tagKeyOwnerName 归属CR的名称
+	tagKeyOwnerName = "eip.alibabacloud.com/owner-name"
+	// tagKeyClusterID 归属集群，值为配置中的clusterID
+	tagKeyClusterID = "eip.alibabacloud.com/cluster-id"
+	// tagKeyProvenance 云上EIP的来源标签，值为Created或Imported
+	tagKeyProvenance = "eip.alibabacloud.com/provenance"
+	// tagKeyManagedBy 由operator创建且仍由CR管理的EIP带有该标签，孤儿回收只扫描带该标签的EIP
+	tagKeyManagedBy = "eip.alibabacloud.com/managed-by"
+
+	managedByValue = "alibabacloud-eip-operator"
+)
+
+// ownershipTags returns the cloud tags identifying the cluster and object that manage a resource
+func ownershipTags(obj client.Object, clusterID string) map[string]string {
+	tags := map[string]string{
+		tagKeyOwnerUID:       string(obj.GetUID()),
+		tagKeyOwnerNamespace: obj.GetNamespace(),
+		tagKeyOwnerName:      obj.GetName(),
+	}
+	if clusterID != "" {
+		tags[tagKeyClusterID] = clusterID
+	}
+	return tags
+}
+
+// ownerUIDSelector returns the tag selector finding the resources created for an object
+func ownerUIDSelector(obj client.Object) map[string]string {
+	return map[string]string{
+		tagKeyOwnerUID: string(obj.GetUID()),
+	}
+}
+
+// operatorTags returns the cloud tags marking a resource as created by this operator in this cluster
+func operatorTags(clusterID string) map[string]string {
+	tags := map[string]string{
+		tagKeyManagedBy: managedByValue,
+	}
+	if clusterID != "" {
+		tags[tagKeyClusterID] = clusterID
+	}
+	return tags
+}
+
+// isOwnershipTagKey reports whether key is an ownership tag maintained by the operator itself
+func isOwnershipTagKey(key string) bool {
+	switch key {
+	case tagKeyOwnerUID, tagKeyOwnerNamespace, tagKeyOwnerName, tagKeyClusterID, tagKeyProvenance, tagKeyManagedBy:
+		return true
+	}
+	return false
+}
+
+// foreignCluster returns the cluster owning a resource when it is not this cluster
+func foreignCluster(tags map[string]string, clusterID string) (string, bool) {
+	owner, ok := tags[tagKeyClusterID]
+	if !ok || owner == clusterID {
+		return "", false
+	}
+	return owner, true
+}
+
+// provenanceFromTags decides the provenance of an EIP from its cloud tags.
+// Only an EIP carrying this object's owner UID can count as created, the provenance tag written
+// together with the UID on adoption keeps imported EIPs imported.
+func provenanceFromTags(obj client.Object, tags map[string]string) eipv1alpha1.EIPProvenance {
+	if uid, ok := tags[tagKeyOwnerUID]; !ok || uid != string(obj.GetUID()) {
+		return eipv1alpha1.EIPProvenanceImported
+	}
+	if tags[tagKeyProvenance] == string(eipv1alpha1.EIPProvenanceImported) {
+		return eipv1alpha1.EIPProvenanceImported
+	}
+	return eipv1alpha1.EIPProvenanceCreated
+}
+
+// releasedOnDelete reports whether deleting the object releases its cloud resource under strategy.
+// OnDeleteIfCreated only releases resources recorded as created, an unknown provenance counts as imported.
+func releasedOnDelete(strategy eipv1alpha1.ReleaseStrategy, provenance eipv1alpha1.EIPProvenance) bool {
+	switch strategy {
+	case eipv1alpha1.ReleaseStrategyOnDelete:
+		return true
+	case eipv1alpha1.ReleaseStrategyOnDeleteIfCreated:
+		return provenance == eipv1alpha1.EIPProvenanceCreated
+	default:
+		return false
+	}
+}
+
+// clientTokenFor returns a ClientToken stable across retries and restarts for the object
+func clientTokenFor(obj client.Object) string {
+	return string(obj.GetUID())
+}
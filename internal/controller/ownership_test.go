@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+func TestProvenanceFromTags(t *testing.T) {
+	eip := &eipv1alpha1.EIP{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "uid-1"}}
+
+	tests := []struct {
+		name string
+		tags map[string]string
+		want eipv1alpha1.EIPProvenance
+	}{
+		{
+			name: "untagged",
+			want: eipv1alpha1.EIPProvenanceImported,
+		},
+		{
+			name: "created for this object",
+			tags: map[string]string{tagKeyOwnerUID: "uid-1"},
+			want: eipv1alpha1.EIPProvenanceCreated,
+		},
+		{
+			name: "created and tagged as created",
+			tags: map[string]string{tagKeyOwnerUID: "uid-1", tagKeyProvenance: string(eipv1alpha1.EIPProvenanceCreated)},
+			want: eipv1alpha1.EIPProvenanceCreated,
+		},
+		{
+			name: "adopted by this object",
+			tags: map[string]string{tagKeyOwnerUID: "uid-1", tagKeyProvenance: string(eipv1alpha1.EIPProvenanceImported)},
+			want: eipv1alpha1.EIPProvenanceImported,
+		},
+		{
+			name: "created for another object",
+			tags: map[string]string{tagKeyOwnerUID: "uid-2", tagKeyProvenance: string(eipv1alpha1.EIPProvenanceCreated)},
+			want: eipv1alpha1.EIPProvenanceImported,
+		},
+		{
+			name: "provenance tag without owner",
+			tags: map[string]string{tagKeyProvenance: string(eipv1alpha1.EIPProvenanceCreated)},
+			want: eipv1alpha1.EIPProvenanceImported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := provenanceFromTags(eip, tt.tags); got != tt.want {
+				t.Errorf("provenanceFromTags() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForeignCluster(t *testing.T) {
+	tests := []struct {
+		name      string
+		tags      map[string]string
+		clusterID string
+		want      string
+		wantOK    bool
+	}{
+		{
+			name:      "untagged",
+			clusterID: "cluster-a",
+		},
+		{
+			name:      "this cluster",
+			tags:      map[string]string{tagKeyClusterID: "cluster-a"},
+			clusterID: "cluster-a",
+		},
+		{
+			name:      "another cluster",
+			tags:      map[string]string{tagKeyClusterID: "cluster-b"},
+			clusterID: "cluster-a",
+			want:      "cluster-b",
+			wantOK:    true,
+		},
+		{
+			name:   "tagged while this cluster has no id",
+			tags:   map[string]string{tagKeyClusterID: "cluster-b"},
+			want:   "cluster-b",
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := foreignCluster(tt.tags, tt.clusterID)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("foreignCluster() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+const (
+	podEIPFinalizer = "eip.alibabacloud.com/pod-eip-finalizer"
+
+	// podKind 写入EIP占用标记时使用的资源类型
+	podKind = "Pod"
+
+	// podENIPhaseBind Terway PodENI 已完成网卡绑定
+	podENIPhaseBind = "Bind"
+)
+
+// podENIGVK Terway 为独占弹性网卡的 Pod 创建的 PodENI 资源
+var podENIGVK = schema.GroupVersionKind{Group: "network.alibabacloud.com", Version: "v1beta1", Kind: "PodENI"}
+
+// PodReconciler assigns EIPs to Terway ENI pods carrying EIP annotations
+type PodReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Record record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=network.alibabacloud.com,resources=podenis,verbs=get;list;watch
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eipassociations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eips,verbs=get;list;watch;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop
+func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	pod := &corev1.Pod{}
+	err := r.Get(ctx, req.NamespacedName, pod)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Release the EIP once the pod goes away or stops asking for one
+	if !pod.DeletionTimestamp.IsZero() || !wantsEIP(pod) || isPodTerminated(pod) {
+		if !controllerutil.ContainsFinalizer(pod, podEIPFinalizer) {
+			return ctrl.Result{}, nil
+		}
+
+		done, err := r.releasePodEIP(ctx, pod)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			return ctrl.Result{RequeueAfter: eipAssociationRequeueAfterPending}, nil
+		}
+
+		controllerutil.RemoveFinalizer(pod, podEIPFinalizer)
+		if pod.DeletionTimestamp.IsZero() {
+			delete(pod.Annotations, eipv1alpha1.AnnotationPodEIPAddress)
+			delete(pod.Annotations, eipv1alpha1.AnnotationPodEIPAllocationID)
+		}
+		return ctrl.Result{}, r.Update(ctx, pod)
+	}
+
+	// Add finalizer if not present
+	if !controllerutil.ContainsFinalizer(pod, podEIPFinalizer) {
+		controllerutil.AddFinalizer(pod, podEIPFinalizer)
+		if err := r.Update(ctx, pod); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	result, err := r.assignPodEIP(ctx, pod)
+	if err != nil {
+		l.Error(err, "failed to assign EIP to pod")
+		r.Record.Eventf(pod, "Warning", "EIPAssignFailed", "Failed to assign EIP: %v", err)
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
+	}
+
+	return result, nil
+}
+
+// assignPodEIP claims an EIP for the pod and associates it with the pod ENI
+func (r *PodReconciler) assignPodEIP(ctx context.Context, pod *corev1.Pod) (ctrl.Result, error) {
+	eip, err := r.claimPodEIP(ctx, pod)
+	if err != nil || eip == nil {
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
+	}
+	if eip.Status.AllocationID == "" {
+		// The EIP watch requeues the pod once the EIP is allocated
+		return ctrl.Result{}, nil
+	}
+
+	eniID, privateIP, err := r.resolvePodENI(ctx, pod)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if eniID == "" {
+		return ctrl.Result{RequeueAfter: eipAssociationRequeueAfterPending}, nil
+	}
+
+	assoc := &eipv1alpha1.EIPAssociation{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+	}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, assoc, func() error {
+		if !assoc.CreationTimestamp.IsZero() && !metav1.IsControlledBy(assoc, pod) {
+			return fmt.Errorf("EIPAssociation %s already exists and is not managed by the pod", assoc.Name)
+		}
+		assoc.Spec.EIPName = eip.Name
+		assoc.Spec.InstanceID = eniID
+		assoc.Spec.InstanceType = eipv1alpha1.AssociationInstanceTypeNetworkInterface
+		assoc.Spec.PrivateIPAddress = privateIP
+		return controllerutil.SetControllerReference(pod, assoc, r.Scheme)
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Record.Eventf(pod, "Normal", "Associating", "Associating EIP %s with ENI %s", eip.Name, eniID)
+	}
+
+	ready := apimeta.IsStatusConditionTrue(assoc.Status.Conditions, conditionTypeReady) && assoc.Status.InstanceID == eniID
+	if !ready {
+		// The owned EIPAssociation watch requeues the pod once it is bound
+		return ctrl.Result{}, nil
+	}
+
+	if pod.Annotations[eipv1alpha1.AnnotationPodEIPAddress] != eip.Status.EIPAddress ||
+		pod.Annotations[eipv1alpha1.AnnotationPodEIPAllocationID] != eip.Status.AllocationID {
+		patch := client.MergeFrom(pod.DeepCopy())
+		pod.Annotations[eipv1alpha1.AnnotationPodEIPAddress] = eip.Status.EIPAddress
+		pod.Annotations[eipv1alpha1.AnnotationPodEIPAllocationID] = eip.Status.AllocationID
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.Record.Eventf(pod, "Normal", "EIPAssigned", "Assigned EIP %s (%s)", eip.Status.EIPAddress, eip.Name)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// claimPodEIP returns the EIP claimed by the pod, claiming one first if needed
+func (r *PodReconciler) claimPodEIP(ctx context.Context, pod *corev1.Pod) (*eipv1alpha1.EIP, error) {
+	claimant := claimantFor(podKind, pod.Name)
+
+	eip, err := findClaimedEIP(ctx, r.Client, pod.Namespace, claimant)
+	if err != nil || eip != nil {
+		return eip, err
+	}
+
+	if name := pod.Annotations[eipv1alpha1.AnnotationPodEIP]; name != "" {
+		eip = &eipv1alpha1.EIP{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: name}, eip); err != nil {
+			if errors.IsNotFound(err) {
+				r.Record.Eventf(pod, "Warning", reasonEIPNotAvailable, "EIP %s not found", name)
+				return nil, nil
+			}
+			return nil, err
+		}
+		ok, err := claimEIP(ctx, r.Client, eip, claimant)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			r.Record.Eventf(pod, "Warning", reasonEIPNotAvailable, "EIP %s is claimed by %s",
+				name, eip.Annotations[eipv1alpha1.AnnotationClaimedBy])
+			return nil, nil
+		}
+		return eip, nil
+	}
+
+	pool := pod.Annotations[eipv1alpha1.AnnotationPodEIPPool]
+	eip, err = claimAvailableEIP(ctx, r.Client, pod.Namespace, map[string]string{eipv1alpha1.LabelEIPPool: pool}, claimant)
+	if err != nil {
+		return nil, err
+	}
+	if eip == nil {
+		r.Record.Eventf(pod, "Warning", reasonPoolExhausted, "No available EIP in pool %s", pool)
+	}
+	return eip, nil
+}
+
+// resolvePodENI returns the ENI and private IP Terway allocated to the pod, empty until the ENI is attached
+func (r *PodReconciler) resolvePodENI(ctx context.Context, pod *corev1.Pod) (string, string, error) {
+	podENI := &unstructured.Unstructured{}
+	podENI.SetGroupVersionKind(podENIGVK)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(pod), podENI); err != nil {
+		if errors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+			return "", "", nil
+		}
+		return "", "", err
+	}
+
+	phase, _, _ := unstructured.NestedString(podENI.Object, "status", "phase")
+	if phase != podENIPhaseBind {
+		return "", "", nil
+	}
+
+	allocations, _, err := unstructured.NestedSlice(podENI.Object, "spec", "allocations")
+	if err != nil || len(allocations) == 0 {
+		return "", "", err
+	}
+	allocation, ok := allocations[0].(map[string]interface{})
+	if !ok {
+		return "", "", fmt.Errorf("unexpected PodENI allocation %v", allocations[0])
+	}
+
+	eniID, _, _ := unstructured.NestedString(allocation, "eni", "id")
+	privateIP, _, _ := unstructured.NestedString(allocation, "ipv4")
+	if privateIP == "" {
+		privateIP = pod.Status.PodIP
+	}
+	return eniID, privateIP, nil
+}
+
+// releasePodEIP unbinds the pod EIP and hands it back according to where it came from.
+// Only EIPs controlled by the pod are deleted, their ReleaseStrategy then decides about the cloud side.
+// EIPs referenced by annotation and pool members are unclaimed and stay for the next user.
+func (r *PodReconciler) releasePodEIP(ctx context.Context, pod *corev1.Pod) (bool, error) {
+	l := log.FromContext(ctx)
+
+	// Unbind first, the EIPAssociation finalizer waits for the cloud side to finish
+	assoc := &eipv1alpha1.EIPAssociation{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(pod), assoc)
+	switch {
+	case err == nil:
+		if !metav1.IsControlledBy(assoc, pod) {
+			break
+		}
+		if assoc.DeletionTimestamp.IsZero() {
+			if err := r.Delete(ctx, assoc); err != nil && !errors.IsNotFound(err) {
+				return false, err
+			}
+		}
+		return false, nil
+	case !errors.IsNotFound(err):
+		return false, err
+	}
+
+	claimant := claimantFor(podKind, pod.Name)
+	eip, err := findClaimedEIP(ctx, r.Client, pod.Namespace, claimant)
+	if err != nil || eip == nil {
+		return err == nil, err
+	}
+
+	if metav1.IsControlledBy(eip, pod) {
+		l.Info("deleting pod EIP", "eip", eip.Name)
+		if err := r.Delete(ctx, eip); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+		r.Record.Eventf(pod, "Normal", "EIPReleased", "Deleted EIP %s following its ReleaseStrategy", eip.Name)
+		return true, nil
+	}
+
+	l.Info("returning pod EIP", "eip", eip.Name)
+	if err := unclaimEIP(ctx, r.Client, client.ObjectKeyFromObject(eip), claimant); err != nil {
+		return false, err
+	}
+	r.Record.Eventf(pod, "Normal", "EIPReturned", "Returned EIP %s", eip.Name)
+	return true, nil
+}
+
+// wantsEIP reports whether the pod asks for an EIP
+func wantsEIP(pod *corev1.Pod) bool {
+	return pod.Annotations[eipv1alpha1.AnnotationPodEIP] != "" || pod.Annotations[eipv1alpha1.AnnotationPodEIPPool] != ""
+}
+
+// isPodTerminated reports whether all containers of the pod have exited for good
+func isPodTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			pod := obj.(*corev1.Pod)
+			return wantsEIP(pod) || controllerutil.ContainsFinalizer(pod, podEIPFinalizer)
+		}))).
+		Owns(&eipv1alpha1.EIPAssociation{}).
+		Watches(&eipv1alpha1.EIP{}, handler.EnqueueRequestsFromMapFunc(podsForEIP)).
+		Complete(r)
+}
+
+// podsForEIP maps an EIP to the pod that claimed it
+func podsForEIP(ctx context.Context, obj client.Object) []reconcile.Request {
+	name, found := strings.CutPrefix(obj.GetAnnotations()[eipv1alpha1.AnnotationClaimedBy], podKind+"/")
+	if !found {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+)
+
+func TestPodReleasesEIP(t *testing.T) {
+	tests := []struct {
+		name string
+		// owned marks the EIP as controlled by the pod, pool puts it in a pool
+		owned bool
+		pool  string
+		// association creates an EIPAssociation controlled by the pod
+		association bool
+
+		wantPodGone bool
+		wantDeleted bool
+		wantClaimed string
+	}{
+		{
+			name:        "EIP controlled by the pod is deleted",
+			owned:       true,
+			wantPodGone: true,
+			wantDeleted: true,
+		},
+		{
+			name:        "referenced EIP is returned",
+			wantPodGone: true,
+		},
+		{
+			name:        "pool member is returned to the pool",
+			pool:        "pool",
+			wantPodGone: true,
+		},
+		{
+			name:        "association is deleted before the EIP is touched",
+			association: true,
+			wantClaimed: "Pod/web-0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := testScheme()
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "web-0", Namespace: "default", UID: "pod-uid",
+					Annotations: map[string]string{eipv1alpha1.AnnotationPodEIP: "web"},
+					Finalizers:  []string{podEIPFinalizer},
+				},
+			}
+			eip := &eipv1alpha1.EIP{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "web", Namespace: "default",
+					Annotations: map[string]string{eipv1alpha1.AnnotationClaimedBy: "Pod/web-0"},
+				},
+				Status: eipv1alpha1.EIPStatus{AllocationID: "eip-1"},
+			}
+			if tt.owned {
+				if err := controllerutil.SetControllerReference(pod, eip, scheme); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.pool != "" {
+				pod.Annotations = map[string]string{eipv1alpha1.AnnotationPodEIPPool: tt.pool}
+				eip.Labels = map[string]string{eipv1alpha1.LabelEIPPool: tt.pool}
+			}
+			objs := []client.Object{pod, eip}
+			assoc := &eipv1alpha1.EIPAssociation{
+				ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace, Finalizers: []string{eipAssociationFinalizer}},
+				Spec:       eipv1alpha1.EIPAssociationSpec{EIPName: eip.Name, InstanceID: "eni-1"},
+			}
+			if tt.association {
+				if err := controllerutil.SetControllerReference(pod, assoc, scheme); err != nil {
+					t.Fatal(err)
+				}
+				objs = append(objs, assoc)
+			}
+
+			c := newFakeClient(objs...)
+			r := &PodReconciler{Client: c, Scheme: scheme, Record: record.NewFakeRecorder(100)}
+			if err := c.Delete(ctx, pod); err != nil {
+				t.Fatal(err)
+			}
+			if err := reconcileN(ctx, r, pod, 1); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			if err := c.Get(ctx, client.ObjectKeyFromObject(pod), pod); errors.IsNotFound(err) != tt.wantPodGone {
+				t.Errorf("Get(pod) error = %v, want pod gone %v", err, tt.wantPodGone)
+			}
+			if tt.association {
+				if err := c.Get(ctx, client.ObjectKeyFromObject(assoc), assoc); err != nil {
+					t.Fatal(err)
+				}
+				if assoc.DeletionTimestamp.IsZero() {
+					t.Errorf("EIPAssociation not deleted")
+				}
+			}
+
+			err := c.Get(ctx, client.ObjectKeyFromObject(eip), eip)
+			if tt.wantDeleted {
+				if !errors.IsNotFound(err) {
+					t.Errorf("Get(eip) error = %v, want the EIP deleted", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := eip.Annotations[eipv1alpha1.AnnotationClaimedBy]; got != tt.wantClaimed {
+				t.Errorf("claimed by %q, want %q", got, tt.wantClaimed)
+			}
+			if eip.Labels[eipv1alpha1.LabelEIPPool] != tt.pool {
+				t.Errorf("pool label = %q, want %q", eip.Labels[eipv1alpha1.LabelEIPPool], tt.pool)
+			}
+		})
+	}
+}
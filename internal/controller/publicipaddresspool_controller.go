@@ -0,0 +1,459 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+const (
+	publicIPAddressPoolFinalizer = "eip.alibabacloud.com/publicipaddresspool-finalizer"
+
+	// resourceTypePublicIPAddressPool 标签接口中公网IP地址池的资源类型
+	resourceTypePublicIPAddressPool = "PUBLICIPADDRESSPOOL"
+
+	// Reasons
+	reasonCidrBlockInUse = "CidrBlockInUse"
+)
+
+// isPublicIPAddressPoolNotFoundError 检查是否为地址池不存在错误
+func isPublicIPAddressPoolNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return strings.Contains(err.Error(), "ResourceNotFound.PublicIpAddressPool")
+}
+
+// PublicIPAddressPoolReconciler reconciles a PublicIPAddressPool object
+type PublicIPAddressPoolReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Record   record.EventRecorder
+	Accounts *CloudAccountClients
+	// ClusterID 写入归属标签的集群标识
+	ClusterID string
+}
+
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=publicipaddresspools,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=publicipaddresspools/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=publicipaddresspools/finalizers,verbs=update
+//+kubebuilder:rbac:groups=eip.alibabacloud.com,resources=eips,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop
+func (r *PublicIPAddressPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	pool := &eipv1alpha1.PublicIPAddressPool{}
+	err := r.Get(ctx, req.NamespacedName, pool)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	var clients aliyunclient.RegionalAPI
+	account, err := r.Accounts.accountFor(ctx, pool.Namespace, pool.Spec.CloudAccountName, pool.Status.CloudAccountName, pool.Status.PublicIPAddressPoolID != "")
+	if err == nil {
+		pool.Status.CloudAccountName = account
+		clients, err = r.Accounts.ForAccount(account)
+	}
+	if err != nil {
+		// Nothing was created in the cloud yet, otherwise deletion also waits for the account
+		if !pool.DeletionTimestamp.IsZero() && pool.Status.PublicIPAddressPoolID == "" {
+			controllerutil.RemoveFinalizer(pool, publicIPAddressPoolFinalizer)
+			return ctrl.Result{}, r.Update(ctx, pool)
+		}
+		l.Info("cloud account is not ready", "cloudAccount", account, "error", err.Error())
+		r.setCondition(pool, conditionTypeReady, metav1.ConditionFalse, reasonCloudAccountNotReady, err.Error())
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, r.updateStatus(ctx, pool)
+	}
+	pool.Status.RegionID = resourceRegion(pool.Spec.RegionID, pool.Status.RegionID, clients)
+	api, err := clients.ForRegion(pool.Status.RegionID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Check if the PublicIPAddressPool instance is marked to be deleted
+	if !pool.ObjectMeta.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(pool, publicIPAddressPoolFinalizer) {
+			if err := r.finalizePool(ctx, api, pool); err != nil {
+				if isThrottlingError(err) {
+					return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterThrottle}, nil
+				}
+				return ctrl.Result{}, err
+			}
+
+			controllerutil.RemoveFinalizer(pool, publicIPAddressPoolFinalizer)
+			if err := r.Update(ctx, pool); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer if not present
+	if !controllerutil.ContainsFinalizer(pool, publicIPAddressPoolFinalizer) {
+		controllerutil.AddFinalizer(pool, publicIPAddressPoolFinalizer)
+		if err := r.Update(ctx, pool); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	result, err := r.reconcilePool(ctx, api, pool)
+	if err != nil {
+		if isThrottlingError(err) {
+			l.Info("API throttled, will retry later")
+			r.setCondition(pool, conditionTypeReady, metav1.ConditionFalse, reasonThrottled, "API throttled, retrying later")
+			_ = r.updateStatus(ctx, pool)
+			return ctrl.Result{RequeueAfter: eipCtrlRequeueAfterThrottle}, nil
+		}
+		l.Error(err, "failed to reconcile PublicIPAddressPool")
+		r.Record.Eventf(pool, "Warning", "ReconcileFailed", "Failed to reconcile PublicIPAddressPool: %v", err)
+		r.setCondition(pool, conditionTypeReady, metav1.ConditionFalse, reasonSyncFailed, err.Error())
+		_ = r.updateStatus(ctx, pool)
+		return ctrl.Result{RequeueAfter: eipCtrlRequeueAfter}, err
+	}
+
+	return result, nil
+}
+
+// reconcilePool creates the pool, converges its CIDR blocks and reports capacity
+func (r *PublicIPAddressPoolReconciler) reconcilePool(ctx context.Context, api aliyunclient.API, pool *eipv1alpha1.PublicIPAddressPool) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	// If PublicIPAddressPoolID is not set, create a new pool
+	if pool.Spec.PublicIPAddressPoolID == "" {
+		if pool.Status.PublicIPAddressPoolID == "" {
+			l.Info("creating new public ip address pool")
+			r.setCondition(pool, conditionTypeProgressing, metav1.ConditionTrue, reasonCreating, "Creating new public ip address pool")
+			if err := r.updateStatus(ctx, pool); err != nil {
+				return ctrl.Result{}, err
+			}
+
+			poolID, err := r.createPool(ctx, api, pool)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+
+			pool.Status.PublicIPAddressPoolID = poolID
+			pool.Status.Provenance = eipv1alpha1.EIPProvenanceCreated
+			if err := r.updateStatus(ctx, pool); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.Record.Eventf(pool, "Normal", "Created", "Created public ip address pool: %s", poolID)
+			r.setCondition(pool, conditionTypeProgressing, metav1.ConditionFalse, reasonCreated, "Public ip address pool created")
+		}
+
+		pool.Spec.PublicIPAddressPoolID = pool.Status.PublicIPAddressPoolID
+		if err := r.Update(ctx, pool); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if pool.Status.PublicIPAddressPoolID == "" && pool.Status.Provenance == "" {
+		// The pool was referenced by ID before this object ever created one
+		pool.Status.Provenance = eipv1alpha1.EIPProvenanceImported
+	}
+
+	blocks, err := api.ListPublicIpAddressPoolCidrBlocks(ctx, pool.Spec.PublicIPAddressPoolID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	changed, err := r.reconcileCidrBlocks(ctx, api, pool, blocks)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if changed {
+		if blocks, err = api.ListPublicIpAddressPoolCidrBlocks(ctx, pool.Spec.PublicIPAddressPoolID); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	r.syncStatus(pool, blocks)
+
+	if pool.Status.Free <= 0 {
+		r.setCondition(pool, conditionTypeReady, metav1.ConditionFalse, reasonPoolExhausted, "No free IP left in the pool")
+	} else {
+		r.setCondition(pool, conditionTypeReady, metav1.ConditionTrue, "Available", "Public ip address pool is ready")
+	}
+	r.setCondition(pool, conditionTypeSynced, metav1.ConditionTrue, "Synced", "Public ip address pool synced successfully")
+	if err := r.updateStatus(ctx, pool); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+// createPool creates the public ip address pool, or recovers the one a previous reconcile created for this object
+func (r *PublicIPAddressPoolReconciler) createPool(ctx context.Context, api aliyunclient.API, pool *eipv1alpha1.PublicIPAddressPool) (string, error) {
+	l := log.FromContext(ctx)
+
+	// A previous reconcile may have created the pool and failed before persisting its ID
+	owned, err := api.ListPublicIpAddressPoolsByTags(ctx, ownerUIDSelector(pool))
+	if err != nil {
+		return "", err
+	}
+	if len(owned) > 0 {
+		if len(owned) > 1 {
+			l.Info("found multiple public ip address pools tagged for this object, recovering the first one", "count", len(owned), "extra", owned[1:])
+			r.Record.Eventf(pool, "Warning", "MultipleOwned", "Found %d public ip address pools tagged for this object, recovering %s, release the extra ones manually: %s",
+				len(owned), owned[0], strings.Join(owned[1:], ", "))
+		}
+		l.Info("recovered previously created public ip address pool", "publicIPAddressPoolID", owned[0])
+		r.Record.Eventf(pool, "Normal", "Recovered", "Recovered previously created public ip address pool: %s", owned[0])
+		return owned[0], nil
+	}
+
+	poolID, err := api.CreatePublicIpAddressPool(ctx, &aliyunclient.PublicIPAddressPoolOptions{
+		ISP:             pool.Spec.ISP,
+		ResourceGroupID: pool.Spec.ResourceGroupID,
+		Name:            pool.Spec.Name,
+		Description:     pool.Spec.Description,
+		ClientToken:     clientTokenFor(pool),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// The ownership tag is what makes the pool recoverable, the retry reuses the ClientToken and gets the same pool back
+	tags := ownershipTags(pool, r.ClusterID)
+	for k, v := range operatorTags(r.ClusterID) {
+		tags[k] = v
+	}
+	if err := api.TagResources(ctx, resourceTypePublicIPAddressPool, []string{poolID}, tags); err != nil {
+		l.Error(err, "failed to tag public ip address pool", "publicIPAddressPoolID", poolID)
+		return "", err
+	}
+
+	return poolID, nil
+}
+
+// reconcileCidrBlocks adds missing CIDR blocks and removes unused ones no longer listed in spec.
+// Blocks are only removed when spec.cidrBlocks is set, so an imported pool keeps its existing blocks.
+func (r *PublicIPAddressPoolReconciler) reconcileCidrBlocks(ctx context.Context, api aliyunclient.API, pool *eipv1alpha1.PublicIPAddressPool, blocks []aliyunclient.PublicIPAddressPoolCidrBlock) (bool, error) {
+	l := log.FromContext(ctx)
+	poolID := pool.Spec.PublicIPAddressPoolID
+
+	wantBlocks := make(map[string]bool)
+	wantMasks := make(map[int]int)
+	for _, block := range pool.Spec.CidrBlocks {
+		if block.CidrBlock != "" {
+			wantBlocks[block.CidrBlock] = true
+		} else if block.CidrMask > 0 {
+			wantMasks[int(block.CidrMask)]++
+		}
+	}
+
+	// Match existing blocks, explicit CIDRs first and then auto-allocated ones by mask
+	var extra []aliyunclient.PublicIPAddressPoolCidrBlock
+	for _, block := range blocks {
+		if wantBlocks[block.CidrBlock] {
+			delete(wantBlocks, block.CidrBlock)
+			continue
+		}
+		if mask := cidrMaskOf(block.CidrBlock); wantMasks[mask] > 0 {
+			wantMasks[mask]--
+			continue
+		}
+		extra = append(extra, block)
+	}
+
+	changed := false
+	for cidr := range wantBlocks {
+		l.Info("adding cidr block", "cidrBlock", cidr)
+		if err := api.AddPublicIpAddressPoolCidrBlock(ctx, poolID, cidr, 0); err != nil {
+			return changed, err
+		}
+		r.Record.Eventf(pool, "Normal", "CidrBlockAdded", "Added cidr block %s", cidr)
+		changed = true
+	}
+	for mask, count := range wantMasks {
+		for i := 0; i < count; i++ {
+			l.Info("adding cidr block", "cidrMask", mask)
+			if err := api.AddPublicIpAddressPoolCidrBlock(ctx, poolID, "", mask); err != nil {
+				return changed, err
+			}
+			r.Record.Eventf(pool, "Normal", "CidrBlockAdded", "Added cidr block with mask /%d", mask)
+			changed = true
+		}
+	}
+
+	if len(pool.Spec.CidrBlocks) == 0 {
+		return changed, nil
+	}
+	for _, block := range extra {
+		if block.UsedIPNum > 0 {
+			r.Record.Eventf(pool, "Warning", reasonCidrBlockInUse,
+				"Cidr block %s is no longer listed but still has %d IPs in use", block.CidrBlock, block.UsedIPNum)
+			continue
+		}
+		l.Info("deleting cidr block", "cidrBlock", block.CidrBlock)
+		if err := api.DeletePublicIpAddressPoolCidrBlock(ctx, poolID, block.CidrBlock); err != nil {
+			return changed, err
+		}
+		r.Record.Eventf(pool, "Normal", "CidrBlockDeleted", "Deleted cidr block %s", block.CidrBlock)
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// syncStatus reports the CIDR blocks and IP capacity of the pool
+func (r *PublicIPAddressPoolReconciler) syncStatus(pool *eipv1alpha1.PublicIPAddressPool, blocks []aliyunclient.PublicIPAddressPoolCidrBlock) {
+	var total, used int32
+	statuses := make([]eipv1alpha1.PublicIPAddressPoolCidrBlockStatus, 0, len(blocks))
+	for _, block := range blocks {
+		statuses = append(statuses, eipv1alpha1.PublicIPAddressPoolCidrBlockStatus{
+			CidrBlock: block.CidrBlock,
+			Status:    block.Status,
+			Total:     int32(block.TotalIPNum),
+			Used:      int32(block.UsedIPNum),
+		})
+		total += int32(block.TotalIPNum)
+		used += int32(block.UsedIPNum)
+	}
+
+	pool.Status.PublicIPAddressPoolID = pool.Spec.PublicIPAddressPoolID
+	pool.Status.CidrBlocks = statuses
+	pool.Status.Total = total
+	pool.Status.Used = used
+	pool.Status.Free = total - used
+
+	now := metav1.Now()
+	pool.Status.LastSyncTime = &now
+}
+
+// finalizePool deletes the CIDR blocks and the pool according to ReleaseStrategy
+func (r *PublicIPAddressPoolReconciler) finalizePool(ctx context.Context, api aliyunclient.API, pool *eipv1alpha1.PublicIPAddressPool) error {
+	l := log.FromContext(ctx)
+	poolID := pool.Status.PublicIPAddressPoolID
+
+	if !releasedOnDelete(pool.Spec.ReleaseStrategy, pool.Status.Provenance) || poolID == "" {
+		l.Info("skipping public ip address pool release", "releaseStrategy", pool.Spec.ReleaseStrategy, "provenance", pool.Status.Provenance)
+		r.Record.Event(pool, "Normal", "Skipped", "Skipped public ip address pool release due to ReleaseStrategy")
+		return nil
+	}
+
+	blocks, err := api.ListPublicIpAddressPoolCidrBlocks(ctx, poolID)
+	if err != nil {
+		if isPublicIPAddressPoolNotFoundError(err) {
+			l.Info("public ip address pool not found, assuming already released", "poolID", poolID)
+			return nil
+		}
+		return err
+	}
+
+	// The pool can only be deleted after all of its CIDR blocks are gone
+	for _, block := range blocks {
+		if block.UsedIPNum > 0 {
+			r.Record.Eventf(pool, "Warning", reasonCidrBlockInUse, "Cidr block %s still has %d IPs in use", block.CidrBlock, block.UsedIPNum)
+			return fmt.Errorf("cidr block %s still has %d IPs in use", block.CidrBlock, block.UsedIPNum)
+		}
+		if err := api.DeletePublicIpAddressPoolCidrBlock(ctx, poolID, block.CidrBlock); err != nil {
+			return err
+		}
+	}
+
+	l.Info("releasing public ip address pool", "poolID", poolID)
+	if err := api.DeletePublicIpAddressPool(ctx, poolID); err != nil && !isPublicIPAddressPoolNotFoundError(err) {
+		r.Record.Eventf(pool, "Warning", "ReleaseFailed", "Failed to release public ip address pool: %v", err)
+		return err
+	}
+	r.Record.Eventf(pool, "Normal", "Released", "Released public ip address pool: %s", poolID)
+	return nil
+}
+
+// cidrMaskOf returns the prefix length of a CIDR block, 0 if it cannot be parsed
+func cidrMaskOf(cidr string) int {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return 0
+	}
+	ones, _ := ipNet.Mask.Size()
+	return ones
+}
+
+// setCondition sets a condition on the PublicIPAddressPool
+func (r *PublicIPAddressPoolReconciler) setCondition(pool *eipv1alpha1.PublicIPAddressPool, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: pool.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	apimeta.SetStatusCondition(&pool.Status.Conditions, condition)
+}
+
+// updateStatus updates the PublicIPAddressPool status
+func (r *PublicIPAddressPoolReconciler) updateStatus(ctx context.Context, pool *eipv1alpha1.PublicIPAddressPool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return r.Status().Update(ctx, pool)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PublicIPAddressPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&eipv1alpha1.PublicIPAddressPool{}).
+		Watches(&eipv1alpha1.EIP{}, handler.EnqueueRequestsFromMapFunc(r.poolsForEIP)).
+		Complete(r)
+}
+
+// poolsForEIP maps an EIP to the PublicIPAddressPool it draws from, so capacity is refreshed quickly
+func (r *PublicIPAddressPoolReconciler) poolsForEIP(ctx context.Context, obj client.Object) []reconcile.Request {
+	poolID := obj.(*eipv1alpha1.EIP).Spec.PublicIPAddressPoolID
+	if poolID == "" {
+		return nil
+	}
+
+	pools := &eipv1alpha1.PublicIPAddressPoolList{}
+	if err := r.List(ctx, pools); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, pool := range pools.Items {
+		if pool.Status.PublicIPAddressPoolID == poolID {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: pool.Name}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
+)
+
+func TestPublicIPAddressPoolLifecycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy eipv1alpha1.ReleaseStrategy
+		// importID references an existing pool, inUse puts an IP in use in its block
+		importID string
+		inUse    bool
+		// recover tags a pool for this object before the first reconcile
+		recover bool
+
+		wantCreated    bool
+		wantProvenance eipv1alpha1.EIPProvenance
+		wantReleased   bool
+		wantDeleteErr  bool
+	}{
+		{
+			name:           "created pool is released by default",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDeleteIfCreated,
+			wantCreated:    true,
+			wantProvenance: eipv1alpha1.EIPProvenanceCreated,
+			wantReleased:   true,
+		},
+		{
+			name:           "imported pool is kept by default",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDeleteIfCreated,
+			importID:       "pippool-imported",
+			wantProvenance: eipv1alpha1.EIPProvenanceImported,
+		},
+		{
+			name:           "imported pool is released on OnDelete",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDelete,
+			importID:       "pippool-imported",
+			wantProvenance: eipv1alpha1.EIPProvenanceImported,
+			wantReleased:   true,
+		},
+		{
+			name:           "pool with IPs in use is not released",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDelete,
+			importID:       "pippool-imported",
+			inUse:          true,
+			wantProvenance: eipv1alpha1.EIPProvenanceImported,
+			wantDeleteErr:  true,
+		},
+		{
+			name:           "pool tagged for this object is recovered",
+			strategy:       eipv1alpha1.ReleaseStrategyOnDeleteIfCreated,
+			recover:        true,
+			wantProvenance: eipv1alpha1.EIPProvenanceCreated,
+			wantReleased:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := newFakeCloud()
+			pool := &eipv1alpha1.PublicIPAddressPool{
+				ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default", UID: "pool-uid"},
+				Spec: eipv1alpha1.PublicIPAddressPoolSpec{
+					PublicIPAddressPoolID: tt.importID,
+					CidrBlocks:            []eipv1alpha1.PublicIPAddressPoolCidrBlock{{CidrMask: 28}},
+					ReleaseStrategy:       tt.strategy,
+				},
+			}
+			if tt.importID != "" {
+				cloud.pools[tt.importID] = []aliyunclient.PublicIPAddressPoolCidrBlock{{CidrBlock: "47.1.0.0/28", TotalIPNum: 16}}
+				if tt.inUse {
+					cloud.pools[tt.importID][0].UsedIPNum = 1
+				}
+			}
+			if tt.recover {
+				cloud.pools["pippool-lost"] = nil
+				cloud.tags["pippool-lost"] = ownerUIDSelector(pool)
+			}
+
+			c := newFakeClient(testNamespace(), pool)
+			r := &PublicIPAddressPoolReconciler{
+				Client:   c,
+				Scheme:   c.Scheme(),
+				Record:   record.NewFakeRecorder(100),
+				Accounts: accountsFor(c, cloud),
+			}
+
+			if err := reconcileN(ctx, r, pool, 1); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(pool), pool); err != nil {
+				t.Fatal(err)
+			}
+			if got := cloud.called("CreatePublicIpAddressPool") == 1; got != tt.wantCreated {
+				t.Errorf("created = %v, want %v", got, tt.wantCreated)
+			}
+			poolID := pool.Status.PublicIPAddressPoolID
+			if poolID == "" || pool.Spec.PublicIPAddressPoolID != poolID {
+				t.Errorf("spec ID %q, status ID %q, want both set", pool.Spec.PublicIPAddressPoolID, poolID)
+			}
+			if pool.Status.Provenance != tt.wantProvenance {
+				t.Errorf("provenance = %s, want %s", pool.Status.Provenance, tt.wantProvenance)
+			}
+			if len(cloud.pools[poolID]) != 1 || pool.Status.Total != 16 {
+				t.Errorf("cidr blocks = %v, total %d, want one /28 block", cloud.pools[poolID], pool.Status.Total)
+			}
+
+			if err := c.Delete(ctx, pool); err != nil {
+				t.Fatal(err)
+			}
+			if err := reconcileN(ctx, r, pool, 1); (err != nil) != tt.wantDeleteErr {
+				t.Fatalf("Reconcile() error = %v, want error %v", err, tt.wantDeleteErr)
+			}
+			if got := cloud.called("DeletePublicIpAddressPool "+poolID) == 1; got != tt.wantReleased {
+				t.Errorf("released = %v, want %v", got, tt.wantReleased)
+			}
+			if tt.wantReleased && cloud.called("DeletePublicIpAddressPoolCidrBlock") != 1 {
+				t.Errorf("cidr block not deleted before the pool: %v", cloud.calls)
+			}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(pool), pool); errors.IsNotFound(err) == tt.wantDeleteErr {
+				t.Errorf("Get() error = %v, want the finalizer kept only while the pool is in use", err)
+			}
+		})
+	}
+}
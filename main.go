@@ -29,6 +29,8 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	eipv1alpha1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1alpha1"
+	eipv1beta1 "github.com/chrisliu1995/alibabacloud-eip-operator/api/v1beta1"
+	"github.com/chrisliu1995/alibabacloud-eip-operator/internal/cloudvalidation"
 	"github.com/chrisliu1995/alibabacloud-eip-operator/internal/controller"
 	aliyunclient "github.com/chrisliu1995/alibabacloud-eip-operator/pkg/aliyun"
 	"github.com/chrisliu1995/alibabacloud-eip-operator/pkg/config"
@@ -42,6 +44,7 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(eipv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(eipv1beta1.AddToScheme(scheme))
 }
 
 func main() {
@@ -70,17 +73,34 @@ func main() {
 		os.Exit(1)
 	}
 	setupLog.Info("loaded config", "config", cfg)
+	if cfg.ClusterID == "" {
+		setupLog.Info("clusterID is not set, EIPs are not tagged with a cluster identity")
+	}
 
-	// 创建阿里云客户端
-	aliyun, err := aliyunclient.NewClient(
-		cfg.AccessKeyID,
-		cfg.AccessKeySecret,
-		cfg.RegionID,
-	)
-	if err != nil {
+	// 创建阿里云客户端，EIP可指定其他地域，对应地域的客户端在首次使用时创建
+	provider := credentialProvider(cfg)
+	setupLog.Info("using credential provider", "provider", cfg.Credentials.Provider, "assumeRole", cfg.Credentials.AssumeRole != nil)
+	newClient := func(provider aliyunclient.CredentialProvider, regionID string) (aliyunclient.API, error) {
+		c, err := aliyunclient.NewClientWithProvider(provider, regionID)
+		if err != nil {
+			return nil, err
+		}
+		c.STSEndpoint = cfg.Credentials.STSEndpoint
+		return c, nil
+	}
+	clients := aliyunclient.NewClientCache(cfg.RegionID, func(regionID string) (aliyunclient.API, error) {
+		return newClient(provider, regionID)
+	})
+	if _, err := clients.ForRegion(""); err != nil {
 		setupLog.Error(err, "unable to create aliyun client")
 		os.Exit(1)
 	}
+	eipv1alpha1.SetDefaultRegionID(cfg.RegionID)
+
+	// 带宽限制表，webhook和控制器共用，按EIP所在地域取生效的规则
+	eipv1alpha1.SetBandwidthLimits(cfg.BandwidthLimits)
+	bandwidthLimits := cfg.BandwidthLimits.ForRegion(cfg.RegionID)
+	setupLog.Info("loaded bandwidth limits", "version", bandwidthLimits.Version, "rules", len(bandwidthLimits.Rules))
 
 	restCfg := ctrl.GetConfigOrDie()
 	restCfg.QPS = cfg.KubeClientQPS
@@ -100,21 +120,153 @@ func main() {
 		os.Exit(1)
 	}
 
+	// EIP按绑定的CloudAccount选择客户端，未绑定时使用控制器自身的凭证
+	accounts := &controller.CloudAccountClients{
+		Client:  mgr.GetClient(),
+		Default: clients,
+	}
+
+	if cfg.IsControllerEnabled("cloudaccount") {
+		if err = (&controller.CloudAccountReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    mgr.GetScheme(),
+			Record:    mgr.GetEventRecorderFor("cloudaccount-controller"),
+			APIReader: mgr.GetAPIReader(),
+			Accounts:  accounts,
+			Provider:  provider,
+			NewClient: newClient,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CloudAccount")
+			os.Exit(1)
+		}
+	}
+
+	// EIP 控制器始终启动，BandwidthPackage 控制器依赖它注册的索引
 	if err = (&controller.EIPReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Record: mgr.GetEventRecorderFor("eip-controller"),
-		Aliyun: aliyun,
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		Record:          mgr.GetEventRecorderFor("eip-controller"),
+		Accounts:        accounts,
+		BandwidthLimits: cfg.BandwidthLimits,
+		ClusterID:       cfg.ClusterID,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "EIP")
 		os.Exit(1)
 	}
 
+	if cfg.IsControllerEnabled("bandwidthpackage") {
+		if err = (&controller.BandwidthPackageReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    mgr.GetScheme(),
+			Record:    mgr.GetEventRecorderFor("bandwidthpackage-controller"),
+			Accounts:  accounts,
+			ClusterID: cfg.ClusterID,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "BandwidthPackage")
+			os.Exit(1)
+		}
+	}
+
+	if cfg.IsControllerEnabled("publicipaddresspool") {
+		if err = (&controller.PublicIPAddressPoolReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    mgr.GetScheme(),
+			Record:    mgr.GetEventRecorderFor("publicipaddresspool-controller"),
+			Accounts:  accounts,
+			ClusterID: cfg.ClusterID,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "PublicIPAddressPool")
+			os.Exit(1)
+		}
+	}
+
+	if cfg.IsControllerEnabled("eipsegment") {
+		if err = (&controller.EIPSegmentReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Record:   mgr.GetEventRecorderFor("eipsegment-controller"),
+			Accounts: accounts,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "EIPSegment")
+			os.Exit(1)
+		}
+	}
+
+	if cfg.IsControllerEnabled("eipassociation") {
+		if err = (&controller.EIPAssociationReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Record:   mgr.GetEventRecorderFor("eipassociation-controller"),
+			Accounts: accounts,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "EIPAssociation")
+			os.Exit(1)
+		}
+	}
+
+	if cfg.IsControllerEnabled("eippool") {
+		if err = (&controller.EIPPoolReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+			Record: mgr.GetEventRecorderFor("eippool-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "EIPPool")
+			os.Exit(1)
+		}
+	}
+
+	if cfg.IsControllerEnabled("eipclaim") {
+		if err = (&controller.EIPClaimReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+			Record: mgr.GetEventRecorderFor("eipclaim-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "EIPClaim")
+			os.Exit(1)
+		}
+	}
+
+	// Pod 控制器需要监听集群内全部Pod，可通过controllers配置关闭
+	if cfg.IsControllerEnabled("pod") {
+		if err = (&controller.PodReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+			Record: mgr.GetEventRecorderFor("pod-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Pod")
+			os.Exit(1)
+		}
+	}
+
+	// 孤儿EIP回收，只在leader上运行
+	if cfg.OrphanGC.Enabled {
+		if err = mgr.Add(&controller.OrphanCollector{
+			Client:    mgr.GetClient(),
+			Record:    mgr.GetEventRecorderFor("orphan-gc"),
+			Accounts:  accounts,
+			Config:    cfg.OrphanGC,
+			ClusterID: cfg.ClusterID,
+		}); err != nil {
+			setupLog.Error(err, "unable to add orphan collector")
+			os.Exit(1)
+		}
+		setupLog.Info("orphan collector enabled", "interval", cfg.OrphanGC.Interval, "gracePeriod", cfg.OrphanGC.GracePeriod,
+			"release", cfg.OrphanGC.Release, "dryRun", cfg.OrphanGC.DryRun)
+	}
+
 	// 设置 Webhook
+	if cfg.CloudValidation.Enabled {
+		eipv1alpha1.SetEIPCloudValidator(cloudvalidation.NewValidator(accounts, cfg.CloudValidation))
+		setupLog.Info("cloud validation enabled", "failurePolicy", cfg.CloudValidation.FailurePolicy, "cacheTTL", cfg.CloudValidation.CacheTTL)
+	}
 	if err = (&eipv1alpha1.EIP{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "EIP")
 		os.Exit(1)
 	}
+	if err = (&eipv1beta1.EIP{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create conversion webhook", "webhook", "EIP")
+		os.Exit(1)
+	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -131,3 +283,36 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// credentialProvider 按配置创建控制器自身的凭证提供者，临时凭证由CachedProvider在过期前刷新
+func credentialProvider(cfg *config.Config) aliyunclient.CredentialProvider {
+	creds := cfg.Credentials
+
+	var provider aliyunclient.CredentialProvider
+	switch creds.Provider {
+	case config.CredentialProviderRRSA:
+		provider = aliyunclient.NewCachedProvider(aliyunclient.NewOIDCProviderFromEnv(aliyunclient.OIDCProvider{
+			RoleARN:         creds.RRSA.RoleARN,
+			OIDCProviderARN: creds.RRSA.OIDCProviderARN,
+			OIDCTokenFile:   creds.RRSA.OIDCTokenFile,
+			RoleSessionName: creds.RRSA.RoleSessionName,
+			STSEndpoint:     creds.STSEndpoint,
+		}))
+	case config.CredentialProviderECSRAMRole:
+		provider = aliyunclient.NewCachedProvider(&aliyunclient.ECSRAMRoleProvider{RoleName: creds.ECSRAMRole.RoleName})
+	default:
+		provider = &aliyunclient.StaticProvider{AccessKeyID: cfg.AccessKeyID, AccessKeySecret: cfg.AccessKeySecret}
+	}
+
+	if role := creds.AssumeRole; role != nil {
+		provider = aliyunclient.NewCachedProvider(&aliyunclient.AssumeRoleProvider{
+			Source:          provider,
+			RoleARN:         role.RoleARN,
+			RoleSessionName: role.RoleSessionName,
+			ExternalID:      role.ExternalID,
+			DurationSeconds: role.DurationSeconds,
+			STSEndpoint:     creds.STSEndpoint,
+		})
+	}
+	return provider
+}
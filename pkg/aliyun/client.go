@@ -18,30 +18,64 @@ package aliyun
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/auth/credentials"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/responses"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
 )
 
 // Client 阿里云客户端
 type Client struct {
 	vpcClient *vpc.Client
+	provider  CredentialProvider
 	regionID  string
+
+	// STSEndpoint GetCallerIdentity使用的STS接入地址，默认DefaultSTSEndpoint
+	STSEndpoint string
 }
 
-// NewClient 创建阿里云客户端
+// NewClient 使用长期有效的AccessKey创建阿里云客户端
 func NewClient(accessKeyID, accessKeySecret, regionID string) (*Client, error) {
-	vpcClient, err := vpc.NewClientWithAccessKey(regionID, accessKeyID, accessKeySecret)
+	return NewClientWithProvider(&StaticProvider{AccessKeyID: accessKeyID, AccessKeySecret: accessKeySecret}, regionID)
+}
+
+// NewClientWithProvider 创建阿里云客户端，每个请求使用provider返回的最新凭证签名
+func NewClientWithProvider(provider CredentialProvider, regionID string) (*Client, error) {
+	// 初始化时的AccessKey只是占位，请求由do按各自获取的凭证签名
+	vpcClient, err := vpc.NewClientWithOptions(regionID, sdk.NewConfig(), credentials.NewAccessKeyCredential("", ""))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create vpc client: %w", err)
 	}
 
 	return &Client{
 		vpcClient: vpcClient,
+		provider:  provider,
 		regionID:  regionID,
 	}, nil
 }
 
+// do sends a request signed with the credentials retrieved for it
+func (c *Client) do(ctx context.Context, req requests.AcsRequest, resp responses.AcsResponse) error {
+	signer, err := signerFor(ctx, c.provider)
+	if err != nil {
+		return err
+	}
+	return c.vpcClient.DoActionWithSigner(req, resp, signer)
+}
+
+// doCommon sends a common request signed with the credentials retrieved for it
+func (c *Client) doCommon(ctx context.Context, req *requests.CommonRequest) (*responses.CommonResponse, error) {
+	signer, err := signerFor(ctx, c.provider)
+	if err != nil {
+		return nil, err
+	}
+	return c.vpcClient.ProcessCommonRequestWithSigner(req, signer)
+}
+
 // AllocateEipAddress 创建EIP
 func (c *Client) AllocateEipAddress(ctx context.Context, opts *EIPOptions) (*EIPAddress, error) {
 	req := vpc.CreateAllocateEipAddressRequest()
@@ -76,9 +110,13 @@ func (c *Client) AllocateEipAddress(ctx context.Context, opts *EIPOptions) (*EIP
 		if len(opts.SecurityProtectionTypes) > 0 {
 			req.SecurityProtectionTypes = &opts.SecurityProtectionTypes
 		}
+		if opts.ClientToken != "" {
+			req.ClientToken = opts.ClientToken
+		}
 	}
 
-	resp, err := c.vpcClient.AllocateEipAddress(req)
+	resp := vpc.CreateAllocateEipAddressResponse()
+	err := c.do(ctx, req, resp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to allocate eip: %w", err)
 	}
@@ -108,48 +146,122 @@ func (c *Client) DescribeEipAddresses(ctx context.Context, allocationID, eipAddr
 		req.AssociatedInstanceType = associatedInstanceType
 	}
 
-	resp, err := c.vpcClient.DescribeEipAddresses(req)
+	resp := vpc.CreateDescribeEipAddressesResponse()
+	err := c.do(ctx, req, resp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe eip addresses: %w", err)
 	}
 
 	result := make([]EIPAddress, 0, len(resp.EipAddresses.EipAddress))
 	for _, eip := range resp.EipAddresses.EipAddress {
-		tags := make(map[string]string)
-		for _, tag := range eip.Tags.Tag {
-			tags[tag.Key] = tag.Value
-		}
-
-		result = append(result, EIPAddress{
-			AllocationID:          eip.AllocationId,
-			Status:                eip.Status,
-			ChargeType:            eip.ChargeType,
-			BandwidthPackageID:    eip.BandwidthPackageId,
-			Bandwidth:             eip.Bandwidth,
-			IPAddress:             eip.IpAddress,
-			InstanceID:            eip.InstanceId,
-			InstanceType:          eip.InstanceType,
-			InternetChargeType:    eip.InternetChargeType,
-			PublicIPAddressPoolID: eip.PublicIpAddressPoolId,
-			ISP:                   eip.ISP,
-			Name:                  eip.Name,
-			ResourceGroupID:       eip.ResourceGroupId,
-			PrivateIPAddress:      eip.PrivateIpAddress,
-			Description:           eip.Descritpion,
-			Tags:                  tags,
+		result = append(result, toEIPAddress(eip))
+	}
+
+	return result, nil
+}
+
+// DescribeEipAddressesBySegment 查询连续EIP地址段中的全部EIP
+func (c *Client) DescribeEipAddressesBySegment(ctx context.Context, segmentID string) ([]EIPAddress, error) {
+	var result []EIPAddress
+
+	for pageNumber := 1; ; pageNumber++ {
+		req := vpc.CreateDescribeEipAddressesRequest()
+		req.Scheme = "https"
+		req.RegionId = c.regionID
+		req.SegmentInstanceId = segmentID
+		req.PageNumber = requests.NewInteger(pageNumber)
+		req.PageSize = requests.NewInteger(100)
+
+		resp := vpc.CreateDescribeEipAddressesResponse()
+		err := c.do(ctx, req, resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe eip addresses: %w", err)
+		}
+
+		for _, eip := range resp.EipAddresses.EipAddress {
+			result = append(result, toEIPAddress(eip))
+		}
+
+		if len(resp.EipAddresses.EipAddress) == 0 || len(result) >= resp.TotalCount {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// DescribeEipAddressesByTags 查询带有全部指定标签的EIP
+func (c *Client) DescribeEipAddressesByTags(ctx context.Context, tags map[string]string) ([]EIPAddress, error) {
+	tagList := make([]vpc.DescribeEipAddressesTag, 0, len(tags))
+	for k, v := range tags {
+		tagList = append(tagList, vpc.DescribeEipAddressesTag{
+			Key:   k,
+			Value: v,
 		})
 	}
 
+	var result []EIPAddress
+	for pageNumber := 1; ; pageNumber++ {
+		req := vpc.CreateDescribeEipAddressesRequest()
+		req.Scheme = "https"
+		req.RegionId = c.regionID
+		req.Tag = &tagList
+		req.PageNumber = requests.NewInteger(pageNumber)
+		req.PageSize = requests.NewInteger(100)
+
+		resp := vpc.CreateDescribeEipAddressesResponse()
+		err := c.do(ctx, req, resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe eip addresses: %w", err)
+		}
+
+		for _, eip := range resp.EipAddresses.EipAddress {
+			result = append(result, toEIPAddress(eip))
+		}
+
+		if len(resp.EipAddresses.EipAddress) == 0 || len(result) >= resp.TotalCount {
+			break
+		}
+	}
+
 	return result, nil
 }
 
+// toEIPAddress 转换SDK返回的EIP信息
+func toEIPAddress(eip vpc.EipAddress) EIPAddress {
+	tags := make(map[string]string)
+	for _, tag := range eip.Tags.Tag {
+		tags[tag.Key] = tag.Value
+	}
+
+	return EIPAddress{
+		AllocationID:          eip.AllocationId,
+		Status:                eip.Status,
+		ChargeType:            eip.ChargeType,
+		BandwidthPackageID:    eip.BandwidthPackageId,
+		Bandwidth:             eip.Bandwidth,
+		IPAddress:             eip.IpAddress,
+		InstanceID:            eip.InstanceId,
+		InstanceType:          eip.InstanceType,
+		InternetChargeType:    eip.InternetChargeType,
+		PublicIPAddressPoolID: eip.PublicIpAddressPoolId,
+		ISP:                   eip.ISP,
+		Name:                  eip.Name,
+		ResourceGroupID:       eip.ResourceGroupId,
+		PrivateIPAddress:      eip.PrivateIpAddress,
+		Description:           eip.Descritpion,
+		DeletionProtection:    eip.DeletionProtection,
+		Tags:                  tags,
+	}
+}
+
 // ReleaseEIPAddress 释放EIP
 func (c *Client) ReleaseEIPAddress(ctx context.Context, eipID string) error {
 	req := vpc.CreateReleaseEipAddressRequest()
 	req.Scheme = "https"
 	req.AllocationId = eipID
 
-	_, err := c.vpcClient.ReleaseEipAddress(req)
+	err := c.do(ctx, req, vpc.CreateReleaseEipAddressResponse())
 	if err != nil {
 		return fmt.Errorf("failed to release eip: %w", err)
 	}
@@ -157,14 +269,37 @@ func (c *Client) ReleaseEIPAddress(ctx context.Context, eipID string) error {
 	return nil
 }
 
+// DeletionProtection 开启或关闭资源的删除保护
+func (c *Client) DeletionProtection(ctx context.Context, resourceType, instanceID string, enable bool) error {
+	req := vpc.CreateDeletionProtectionRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+	req.Type = resourceType
+	req.InstanceId = instanceID
+	req.ProtectionEnable = requests.NewBoolean(enable)
+
+	err := c.do(ctx, req, vpc.CreateDeletionProtectionResponse())
+	if err != nil {
+		return fmt.Errorf("failed to set deletion protection: %w", err)
+	}
+
+	return nil
+}
+
 // ModifyEipAddressAttribute 修改EIP属性
-func (c *Client) ModifyEipAddressAttribute(ctx context.Context, allocationID string, bandwidth string) error {
+func (c *Client) ModifyEipAddressAttribute(ctx context.Context, allocationID string, attrs *EIPAttributes) error {
+	if attrs.IsEmpty() {
+		return nil
+	}
+
 	req := vpc.CreateModifyEipAddressAttributeRequest()
 	req.Scheme = "https"
 	req.AllocationId = allocationID
-	req.Bandwidth = bandwidth
+	req.Bandwidth = attrs.Bandwidth
+	req.Name = attrs.Name
+	req.Description = attrs.Description
 
-	_, err := c.vpcClient.ModifyEipAddressAttribute(req)
+	err := c.do(ctx, req, vpc.CreateModifyEipAddressAttributeResponse())
 	if err != nil {
 		return fmt.Errorf("failed to modify eip attribute: %w", err)
 	}
@@ -172,6 +307,131 @@ func (c *Client) ModifyEipAddressAttribute(ctx context.Context, allocationID str
 	return nil
 }
 
+// AssociateEipAddress 将EIP绑定到云产品实例
+func (c *Client) AssociateEipAddress(ctx context.Context, allocationID, instanceID, instanceType, privateIPAddress string) error {
+	req := vpc.CreateAssociateEipAddressRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+	req.AllocationId = allocationID
+	req.InstanceId = instanceID
+
+	if instanceType != "" {
+		req.InstanceType = instanceType
+	}
+	if privateIPAddress != "" {
+		req.PrivateIpAddress = privateIPAddress
+	}
+
+	err := c.do(ctx, req, vpc.CreateAssociateEipAddressResponse())
+	if err != nil {
+		return fmt.Errorf("failed to associate eip: %w", err)
+	}
+
+	return nil
+}
+
+// UnassociateEipAddress 将EIP从云产品实例上解绑
+func (c *Client) UnassociateEipAddress(ctx context.Context, allocationID, instanceID, instanceType, privateIPAddress string) error {
+	req := vpc.CreateUnassociateEipAddressRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+	req.AllocationId = allocationID
+	req.InstanceId = instanceID
+
+	if instanceType != "" {
+		req.InstanceType = instanceType
+	}
+	if privateIPAddress != "" {
+		req.PrivateIpAddress = privateIPAddress
+	}
+
+	err := c.do(ctx, req, vpc.CreateUnassociateEipAddressResponse())
+	if err != nil {
+		return fmt.Errorf("failed to unassociate eip: %w", err)
+	}
+
+	return nil
+}
+
+// AllocateEipSegmentAddress 申请连续EIP地址段
+func (c *Client) AllocateEipSegmentAddress(ctx context.Context, opts *EIPSegmentOptions) (string, error) {
+	req := vpc.CreateAllocateEipSegmentAddressRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+	req.Netmode = "public"
+
+	if opts != nil {
+		req.EipMask = opts.EIPMask
+		if opts.Bandwidth != "" {
+			req.Bandwidth = opts.Bandwidth
+		}
+		if opts.InternetChargeType != "" {
+			req.InternetChargeType = opts.InternetChargeType
+		}
+		if opts.ISP != "" {
+			req.Isp = opts.ISP
+		}
+		if opts.ResourceGroupID != "" {
+			req.ResourceGroupId = opts.ResourceGroupID
+		}
+		if opts.ClientToken != "" {
+			req.ClientToken = opts.ClientToken
+		}
+	}
+
+	resp := vpc.CreateAllocateEipSegmentAddressResponse()
+	err := c.do(ctx, req, resp)
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate eip segment: %w", err)
+	}
+
+	return resp.EipSegmentInstanceId, nil
+}
+
+// DescribeEipSegment 查询连续EIP地址段
+func (c *Client) DescribeEipSegment(ctx context.Context, segmentID string) ([]EIPSegment, error) {
+	req := vpc.CreateDescribeEipSegmentRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+
+	if segmentID != "" {
+		req.SegmentInstanceId = segmentID
+	}
+
+	resp := vpc.CreateDescribeEipSegmentResponse()
+	err := c.do(ctx, req, resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe eip segment: %w", err)
+	}
+
+	result := make([]EIPSegment, 0, len(resp.EipSegments.EipSegment))
+	for _, segment := range resp.EipSegments.EipSegment {
+		result = append(result, EIPSegment{
+			SegmentInstanceID: segment.InstanceId,
+			Segment:           segment.Segment,
+			Status:            segment.Status,
+			IPCount:           segment.IpCount,
+		})
+	}
+
+	return result, nil
+}
+
+// ReleaseEipSegmentAddress 释放连续EIP地址段
+func (c *Client) ReleaseEipSegmentAddress(ctx context.Context, segmentID string) error {
+	req := vpc.CreateReleaseEipSegmentAddressRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+	req.SegmentInstanceId = segmentID
+
+	err := c.do(ctx, req, vpc.CreateReleaseEipSegmentAddressResponse())
+	if err != nil {
+		return fmt.Errorf("failed to release eip segment: %w", err)
+	}
+
+	return nil
+}
+
 // AddCommonBandwidthPackageIP 添加EIP到带宽包
 func (c *Client) AddCommonBandwidthPackageIP(ctx context.Context, eipID, packageID string) error {
 	req := vpc.CreateAddCommonBandwidthPackageIpRequest()
@@ -179,7 +439,7 @@ func (c *Client) AddCommonBandwidthPackageIP(ctx context.Context, eipID, package
 	req.IpInstanceId = eipID
 	req.BandwidthPackageId = packageID
 
-	_, err := c.vpcClient.AddCommonBandwidthPackageIp(req)
+	err := c.do(ctx, req, vpc.CreateAddCommonBandwidthPackageIpResponse())
 	if err != nil {
 		return fmt.Errorf("failed to add eip to bandwidth package: %w", err)
 	}
@@ -194,7 +454,7 @@ func (c *Client) RemoveCommonBandwidthPackageIP(ctx context.Context, eipID, pack
 	req.IpInstanceId = eipID
 	req.BandwidthPackageId = packageID
 
-	_, err := c.vpcClient.RemoveCommonBandwidthPackageIp(req)
+	err := c.do(ctx, req, vpc.CreateRemoveCommonBandwidthPackageIpResponse())
 	if err != nil {
 		return fmt.Errorf("failed to remove eip from bandwidth package: %w", err)
 	}
@@ -202,6 +462,323 @@ func (c *Client) RemoveCommonBandwidthPackageIP(ctx context.Context, eipID, pack
 	return nil
 }
 
+// CreateCommonBandwidthPackage 创建共享带宽包
+func (c *Client) CreateCommonBandwidthPackage(ctx context.Context, opts *BandwidthPackageOptions) (string, error) {
+	req := vpc.CreateCreateCommonBandwidthPackageRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+
+	if opts != nil {
+		req.Bandwidth = requests.Integer(opts.Bandwidth)
+		if opts.InternetChargeType != "" {
+			req.InternetChargeType = opts.InternetChargeType
+		}
+		if opts.ISP != "" {
+			req.ISP = opts.ISP
+		}
+		if opts.ResourceGroupID != "" {
+			req.ResourceGroupId = opts.ResourceGroupID
+		}
+		if opts.Name != "" {
+			req.Name = opts.Name
+		}
+		if opts.Description != "" {
+			req.Description = opts.Description
+		}
+		if len(opts.SecurityProtectionTypes) > 0 {
+			req.SecurityProtectionTypes = &opts.SecurityProtectionTypes
+		}
+		if opts.ClientToken != "" {
+			req.ClientToken = opts.ClientToken
+		}
+	}
+
+	resp := vpc.CreateCreateCommonBandwidthPackageResponse()
+	err := c.do(ctx, req, resp)
+	if err != nil {
+		return "", fmt.Errorf("failed to create bandwidth package: %w", err)
+	}
+
+	return resp.BandwidthPackageId, nil
+}
+
+// DescribeCommonBandwidthPackages 查询共享带宽包
+func (c *Client) DescribeCommonBandwidthPackages(ctx context.Context, packageID string) ([]BandwidthPackage, error) {
+	req := vpc.CreateDescribeCommonBandwidthPackagesRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+
+	if packageID != "" {
+		req.BandwidthPackageId = packageID
+	}
+
+	resp := vpc.CreateDescribeCommonBandwidthPackagesResponse()
+	err := c.do(ctx, req, resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe bandwidth packages: %w", err)
+	}
+
+	result := make([]BandwidthPackage, 0, len(resp.CommonBandwidthPackages.CommonBandwidthPackage))
+	for _, pkg := range resp.CommonBandwidthPackages.CommonBandwidthPackage {
+		result = append(result, toBandwidthPackage(pkg))
+	}
+
+	return result, nil
+}
+
+// DescribeCommonBandwidthPackagesByTags 查询带有全部指定标签的共享带宽包
+func (c *Client) DescribeCommonBandwidthPackagesByTags(ctx context.Context, tags map[string]string) ([]BandwidthPackage, error) {
+	tagList := make([]vpc.DescribeCommonBandwidthPackagesTag, 0, len(tags))
+	for k, v := range tags {
+		tagList = append(tagList, vpc.DescribeCommonBandwidthPackagesTag{
+			Key:   k,
+			Value: v,
+		})
+	}
+
+	var result []BandwidthPackage
+	for pageNumber := 1; ; pageNumber++ {
+		req := vpc.CreateDescribeCommonBandwidthPackagesRequest()
+		req.Scheme = "https"
+		req.RegionId = c.regionID
+		req.Tag = &tagList
+		req.PageNumber = requests.NewInteger(pageNumber)
+		req.PageSize = requests.NewInteger(50)
+
+		resp := vpc.CreateDescribeCommonBandwidthPackagesResponse()
+		err := c.do(ctx, req, resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe bandwidth packages: %w", err)
+		}
+
+		for _, pkg := range resp.CommonBandwidthPackages.CommonBandwidthPackage {
+			result = append(result, toBandwidthPackage(pkg))
+		}
+
+		if len(resp.CommonBandwidthPackages.CommonBandwidthPackage) == 0 || len(result) >= resp.TotalCount {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// toBandwidthPackage 转换SDK返回的共享带宽包信息
+func toBandwidthPackage(pkg vpc.CommonBandwidthPackage) BandwidthPackage {
+	ips := make([]BandwidthPackageIP, 0, len(pkg.PublicIpAddresses.PublicIpAddresse))
+	for _, ip := range pkg.PublicIpAddresses.PublicIpAddresse {
+		ips = append(ips, BandwidthPackageIP{
+			AllocationID: ip.AllocationId,
+			IPAddress:    ip.IpAddress,
+		})
+	}
+
+	return BandwidthPackage{
+		BandwidthPackageID: pkg.BandwidthPackageId,
+		Status:             pkg.Status,
+		Bandwidth:          pkg.Bandwidth,
+		InternetChargeType: pkg.InternetChargeType,
+		ISP:                pkg.ISP,
+		Name:               pkg.Name,
+		Description:        pkg.Description,
+		ResourceGroupID:    pkg.ResourceGroupId,
+		PublicIPAddresses:  ips,
+	}
+}
+
+// ModifyCommonBandwidthPackageSpec 修改共享带宽包带宽
+func (c *Client) ModifyCommonBandwidthPackageSpec(ctx context.Context, packageID, bandwidth string) error {
+	req := vpc.CreateModifyCommonBandwidthPackageSpecRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+	req.BandwidthPackageId = packageID
+	req.Bandwidth = bandwidth
+
+	err := c.do(ctx, req, vpc.CreateModifyCommonBandwidthPackageSpecResponse())
+	if err != nil {
+		return fmt.Errorf("failed to modify bandwidth package spec: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCommonBandwidthPackage 删除共享带宽包，带宽包中剩余的EIP会被自动移出
+func (c *Client) DeleteCommonBandwidthPackage(ctx context.Context, packageID string) error {
+	req := vpc.CreateDeleteCommonBandwidthPackageRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+	req.BandwidthPackageId = packageID
+	req.Force = "true"
+
+	err := c.do(ctx, req, vpc.CreateDeleteCommonBandwidthPackageResponse())
+	if err != nil {
+		return fmt.Errorf("failed to delete bandwidth package: %w", err)
+	}
+
+	return nil
+}
+
+// CreatePublicIpAddressPool 创建公网IP地址池
+func (c *Client) CreatePublicIpAddressPool(ctx context.Context, opts *PublicIPAddressPoolOptions) (string, error) {
+	req := vpc.CreateCreatePublicIpAddressPoolRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+
+	if opts != nil {
+		if opts.ISP != "" {
+			req.Isp = opts.ISP
+		}
+		if opts.ResourceGroupID != "" {
+			req.ResourceGroupId = opts.ResourceGroupID
+		}
+		if opts.Name != "" {
+			req.Name = opts.Name
+		}
+		if opts.Description != "" {
+			req.Description = opts.Description
+		}
+		if opts.ClientToken != "" {
+			req.ClientToken = opts.ClientToken
+		}
+	}
+
+	resp := vpc.CreateCreatePublicIpAddressPoolResponse()
+	err := c.do(ctx, req, resp)
+	if err != nil {
+		return "", fmt.Errorf("failed to create public ip address pool: %w", err)
+	}
+
+	return resp.PulbicIpAddressPoolId, nil
+}
+
+// ListPublicIpAddressPoolsByTags 查询带有全部指定标签的公网IP地址池，返回地址池ID
+func (c *Client) ListPublicIpAddressPoolsByTags(ctx context.Context, tags map[string]string) ([]string, error) {
+	tagList := make([]vpc.ListPublicIpAddressPoolsTags, 0, len(tags))
+	for k, v := range tags {
+		tagList = append(tagList, vpc.ListPublicIpAddressPoolsTags{
+			Key:   k,
+			Value: v,
+		})
+	}
+
+	var result []string
+	nextToken := ""
+	for {
+		req := vpc.CreateListPublicIpAddressPoolsRequest()
+		req.Scheme = "https"
+		req.RegionId = c.regionID
+		req.Tags = &tagList
+		req.MaxResults = requests.NewInteger(100)
+		req.NextToken = nextToken
+
+		resp := vpc.CreateListPublicIpAddressPoolsResponse()
+		err := c.do(ctx, req, resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list public ip address pools: %w", err)
+		}
+
+		for _, pool := range resp.PublicIpAddressPoolList {
+			result = append(result, pool.PublicIpAddressPoolId)
+		}
+
+		if resp.NextToken == "" {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	return result, nil
+}
+
+// DeletePublicIpAddressPool 删除公网IP地址池
+func (c *Client) DeletePublicIpAddressPool(ctx context.Context, poolID string) error {
+	req := vpc.CreateDeletePublicIpAddressPoolRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+	req.PublicIpAddressPoolId = poolID
+
+	err := c.do(ctx, req, vpc.CreateDeletePublicIpAddressPoolResponse())
+	if err != nil {
+		return fmt.Errorf("failed to delete public ip address pool: %w", err)
+	}
+
+	return nil
+}
+
+// AddPublicIpAddressPoolCidrBlock 为公网IP地址池添加IP地址段，cidrBlock为空时按cidrMask自动分配
+func (c *Client) AddPublicIpAddressPoolCidrBlock(ctx context.Context, poolID, cidrBlock string, cidrMask int) error {
+	req := vpc.CreateAddPublicIpAddressPoolCidrBlockRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+	req.PublicIpAddressPoolId = poolID
+
+	if cidrBlock != "" {
+		req.CidrBlock = cidrBlock
+	} else {
+		req.CidrMask = requests.NewInteger(cidrMask)
+	}
+
+	err := c.do(ctx, req, vpc.CreateAddPublicIpAddressPoolCidrBlockResponse())
+	if err != nil {
+		return fmt.Errorf("failed to add public ip address pool cidr block: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePublicIpAddressPoolCidrBlock 删除公网IP地址池中的IP地址段
+func (c *Client) DeletePublicIpAddressPoolCidrBlock(ctx context.Context, poolID, cidrBlock string) error {
+	req := vpc.CreateDeletePublicIpAddressPoolCidrBlockRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+	req.PublicIpAddressPoolId = poolID
+	req.CidrBlock = cidrBlock
+
+	err := c.do(ctx, req, vpc.CreateDeletePublicIpAddressPoolCidrBlockResponse())
+	if err != nil {
+		return fmt.Errorf("failed to delete public ip address pool cidr block: %w", err)
+	}
+
+	return nil
+}
+
+// ListPublicIpAddressPoolCidrBlocks 查询公网IP地址池中的全部IP地址段
+func (c *Client) ListPublicIpAddressPoolCidrBlocks(ctx context.Context, poolID string) ([]PublicIPAddressPoolCidrBlock, error) {
+	var result []PublicIPAddressPoolCidrBlock
+
+	nextToken := ""
+	for {
+		req := vpc.CreateListPublicIpAddressPoolCidrBlocksRequest()
+		req.Scheme = "https"
+		req.RegionId = c.regionID
+		req.PublicIpAddressPoolId = poolID
+		req.MaxResults = requests.NewInteger(100)
+		req.NextToken = nextToken
+
+		resp := vpc.CreateListPublicIpAddressPoolCidrBlocksResponse()
+		err := c.do(ctx, req, resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list public ip address pool cidr blocks: %w", err)
+		}
+
+		for _, block := range resp.PublicIpPoolCidrBlockList {
+			result = append(result, PublicIPAddressPoolCidrBlock{
+				CidrBlock:  block.CidrBlock,
+				Status:     block.Status,
+				TotalIPNum: block.TotalIpNum,
+				UsedIPNum:  block.UsedIpNum,
+			})
+		}
+
+		if resp.NextToken == "" {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	return result, nil
+}
+
 // TagResources 为资源打标签
 func (c *Client) TagResources(ctx context.Context, resourceType string, resourceIDs []string, tags map[string]string) error {
 	if len(resourceIDs) == 0 || len(tags) == 0 {
@@ -223,10 +800,133 @@ func (c *Client) TagResources(ctx context.Context, resourceType string, resource
 	}
 	req.Tag = &tagList
 
-	_, err := c.vpcClient.TagResources(req)
+	err := c.do(ctx, req, vpc.CreateTagResourcesResponse())
 	if err != nil {
 		return fmt.Errorf("failed to tag resources: %w", err)
 	}
 
 	return nil
 }
+
+// UntagResources 移除资源标签
+func (c *Client) UntagResources(ctx context.Context, resourceType string, resourceIDs []string, tagKeys []string) error {
+	if len(resourceIDs) == 0 || len(tagKeys) == 0 {
+		return nil
+	}
+
+	req := vpc.CreateUnTagResourcesRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+	req.ResourceType = resourceType
+	req.ResourceId = &resourceIDs
+	req.TagKey = &tagKeys
+
+	err := c.do(ctx, req, vpc.CreateUnTagResourcesResponse())
+	if err != nil {
+		return fmt.Errorf("failed to untag resources: %w", err)
+	}
+
+	return nil
+}
+
+// ListTagResources 查询资源上的全部标签
+func (c *Client) ListTagResources(ctx context.Context, resourceType string, resourceID string) (map[string]string, error) {
+	tags := make(map[string]string)
+	resourceIDs := []string{resourceID}
+
+	nextToken := ""
+	for {
+		req := vpc.CreateListTagResourcesRequest()
+		req.Scheme = "https"
+		req.RegionId = c.regionID
+		req.ResourceType = resourceType
+		req.ResourceId = &resourceIDs
+		req.MaxResults = requests.NewInteger(50)
+		req.NextToken = nextToken
+
+		resp := vpc.CreateListTagResourcesResponse()
+		err := c.do(ctx, req, resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tag resources: %w", err)
+		}
+
+		for _, tag := range resp.TagResources.TagResource {
+			tags[tag.TagKey] = tag.TagValue
+		}
+
+		if resp.NextToken == "" {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	return tags, nil
+}
+
+// GetResourceGroup 查询资源组
+// 资源组属于资源管理服务，使用通用请求调用，避免引入额外的SDK客户端
+func (c *Client) GetResourceGroup(ctx context.Context, resourceGroupID string) (*ResourceGroup, error) {
+	req := requests.NewCommonRequest()
+	req.Method = requests.POST
+	req.Scheme = "https"
+	req.Domain = "resourcemanager.aliyuncs.com"
+	req.Product = "ResourceManager"
+	req.Version = "2020-03-31"
+	req.ApiName = "GetResourceGroup"
+	req.QueryParams["ResourceGroupId"] = resourceGroupID
+
+	resp, err := c.doCommon(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource group: %w", err)
+	}
+
+	var result struct {
+		ResourceGroup struct {
+			Id          string `json:"Id"`
+			Name        string `json:"Name"`
+			DisplayName string `json:"DisplayName"`
+			Status      string `json:"Status"`
+		} `json:"ResourceGroup"`
+	}
+	if err := json.Unmarshal(resp.GetHttpContentBytes(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse resource group: %w", err)
+	}
+
+	return &ResourceGroup{
+		ID:          result.ResourceGroup.Id,
+		Name:        result.ResourceGroup.Name,
+		DisplayName: result.ResourceGroup.DisplayName,
+		Status:      result.ResourceGroup.Status,
+	}, nil
+}
+
+// GetCallerIdentity 查询当前凭证对应的身份，用于校验凭证是否有效
+func (c *Client) GetCallerIdentity(ctx context.Context) (*CallerIdentity, error) {
+	req := requests.NewCommonRequest()
+	req.Method = requests.POST
+	req.Scheme = "https"
+	req.Domain = stsEndpointOrDefault(c.STSEndpoint)
+	req.Product = "Sts"
+	req.Version = "2015-04-01"
+	req.ApiName = "GetCallerIdentity"
+
+	resp, err := c.doCommon(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+
+	var result struct {
+		AccountId    string `json:"AccountId"`
+		Arn          string `json:"Arn"`
+		IdentityType string `json:"IdentityType"`
+	}
+	if err := json.Unmarshal(resp.GetHttpContentBytes(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse caller identity: %w", err)
+	}
+
+	return &CallerIdentity{
+		AccountID:    result.AccountId,
+		Arn:          result.Arn,
+		IdentityType: result.IdentityType,
+	}, nil
+}
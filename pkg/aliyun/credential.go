@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aliyun
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// DefaultRoleSessionName 扮演RAM角色时默认的会话名称
+const DefaultRoleSessionName = "alibabacloud-eip-operator"
+
+// Credential CloudAccount使用的凭证
+type Credential struct {
+	// AccessKeyID/AccessKeySecret 为空时以控制器自身的凭证为基础
+	AccessKeyID     string
+	AccessKeySecret string
+	// RoleARN 不为空时扮演该RAM角色
+	RoleARN         string
+	RoleSessionName string
+}
+
+// Provider 返回该凭证的提供者，未设置AccessKey时在source之后扮演角色
+func (c Credential) Provider(source CredentialProvider) CredentialProvider {
+	provider := source
+	if c.AccessKeyID != "" {
+		provider = &StaticProvider{AccessKeyID: c.AccessKeyID, AccessKeySecret: c.AccessKeySecret}
+	}
+	if c.RoleARN != "" {
+		provider = NewCachedProvider(&AssumeRoleProvider{
+			Source:          provider,
+			RoleARN:         c.RoleARN,
+			RoleSessionName: c.RoleSessionName,
+		})
+	}
+	return provider
+}
+
+// Fingerprint 返回凭证的摘要，用于判断凭证是否变化，不会暴露密钥
+func (c Credential) Fingerprint() string {
+	h := sha256.New()
+	for _, v := range []string{c.AccessKeyID, c.AccessKeySecret, c.RoleARN, c.RoleSessionName} {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -18,6 +18,7 @@ package aliyun
 
 import (
 	"context"
+	"strings"
 )
 
 // API 阿里云VPC API接口
@@ -26,14 +27,45 @@ type API interface {
 	AllocateEipAddress(ctx context.Context, opts *EIPOptions) (*EIPAddress, error)
 	DescribeEipAddresses(ctx context.Context, allocationID, eipAddress, associatedInstanceID, associatedInstanceType string) ([]EIPAddress, error)
 	ReleaseEIPAddress(ctx context.Context, eipID string) error
-	ModifyEipAddressAttribute(ctx context.Context, allocationID string, bandwidth string) error
+	ModifyEipAddressAttribute(ctx context.Context, allocationID string, attrs *EIPAttributes) error
+	AssociateEipAddress(ctx context.Context, allocationID, instanceID, instanceType, privateIPAddress string) error
+	UnassociateEipAddress(ctx context.Context, allocationID, instanceID, instanceType, privateIPAddress string) error
+	DescribeEipAddressesBySegment(ctx context.Context, segmentID string) ([]EIPAddress, error)
+	DescribeEipAddressesByTags(ctx context.Context, tags map[string]string) ([]EIPAddress, error)
+	DeletionProtection(ctx context.Context, resourceType, instanceID string, enable bool) error
+
+	// 连续EIP地址段相关接口
+	AllocateEipSegmentAddress(ctx context.Context, opts *EIPSegmentOptions) (string, error)
+	DescribeEipSegment(ctx context.Context, segmentID string) ([]EIPSegment, error)
+	ReleaseEipSegmentAddress(ctx context.Context, segmentID string) error
 
 	// 带宽包相关接口
 	AddCommonBandwidthPackageIP(ctx context.Context, eipID, packageID string) error
 	RemoveCommonBandwidthPackageIP(ctx context.Context, eipID, packageID string) error
+	CreateCommonBandwidthPackage(ctx context.Context, opts *BandwidthPackageOptions) (string, error)
+	DescribeCommonBandwidthPackages(ctx context.Context, packageID string) ([]BandwidthPackage, error)
+	DescribeCommonBandwidthPackagesByTags(ctx context.Context, tags map[string]string) ([]BandwidthPackage, error)
+	ModifyCommonBandwidthPackageSpec(ctx context.Context, packageID, bandwidth string) error
+	DeleteCommonBandwidthPackage(ctx context.Context, packageID string) error
+
+	// 公网IP地址池相关接口
+	CreatePublicIpAddressPool(ctx context.Context, opts *PublicIPAddressPoolOptions) (string, error)
+	ListPublicIpAddressPoolsByTags(ctx context.Context, tags map[string]string) ([]string, error)
+	DeletePublicIpAddressPool(ctx context.Context, poolID string) error
+	AddPublicIpAddressPoolCidrBlock(ctx context.Context, poolID, cidrBlock string, cidrMask int) error
+	DeletePublicIpAddressPoolCidrBlock(ctx context.Context, poolID, cidrBlock string) error
+	ListPublicIpAddressPoolCidrBlocks(ctx context.Context, poolID string) ([]PublicIPAddressPoolCidrBlock, error)
 
 	// 标签相关接口
 	TagResources(ctx context.Context, resourceType string, resourceIDs []string, tags map[string]string) error
+	UntagResources(ctx context.Context, resourceType string, resourceIDs []string, tagKeys []string) error
+	ListTagResources(ctx context.Context, resourceType string, resourceID string) (map[string]string, error)
+
+	// 资源组相关接口
+	GetResourceGroup(ctx context.Context, resourceGroupID string) (*ResourceGroup, error)
+
+	// 身份相关接口
+	GetCallerIdentity(ctx context.Context) (*CallerIdentity, error)
 }
 
 // EIPOptions EIP创建选项
@@ -47,6 +79,35 @@ type EIPOptions struct {
 	Name                    string
 	Description             string
 	SecurityProtectionTypes []string
+	// ClientToken 保证请求幂等，相同ClientToken的重复请求返回同一个EIP
+	ClientToken string
+}
+
+// EIPAttributes EIP可原地修改的属性，空值表示不修改
+type EIPAttributes struct {
+	Bandwidth   string
+	Name        string
+	Description string
+}
+
+// String 返回需要修改的属性，用于日志和事件
+func (a *EIPAttributes) String() string {
+	var parts []string
+	if a.Bandwidth != "" {
+		parts = append(parts, "bandwidth="+a.Bandwidth)
+	}
+	if a.Name != "" {
+		parts = append(parts, "name="+a.Name)
+	}
+	if a.Description != "" {
+		parts = append(parts, "description="+a.Description)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// IsEmpty 是否没有需要修改的属性
+func (a *EIPAttributes) IsEmpty() bool {
+	return a == nil || (a.Bandwidth == "" && a.Name == "" && a.Description == "")
 }
 
 // EIPAddress EIP地址信息
@@ -66,10 +127,83 @@ type EIPAddress struct {
 	ResourceGroupID       string
 	PrivateIPAddress      string
 	Description           string
+	DeletionProtection    bool
 	Tags                  map[string]string
 }
 
+// EIPSegmentOptions 连续EIP地址段申请选项
+type EIPSegmentOptions struct {
+	EIPMask            string
+	Bandwidth          string
+	InternetChargeType string
+	ISP                string
+	ResourceGroupID    string
+	// ClientToken 保证请求幂等，相同ClientToken的重复请求返回同一个地址段
+	ClientToken string
+}
+
+// EIPSegment 连续EIP地址段信息
+type EIPSegment struct {
+	SegmentInstanceID string
+	Segment           string
+	Status            string
+	IPCount           string
+}
+
+// BandwidthPackageOptions 共享带宽包创建选项
+type BandwidthPackageOptions struct {
+	Bandwidth               string
+	InternetChargeType      string
+	ISP                     string
+	ResourceGroupID         string
+	Name                    string
+	Description             string
+	SecurityProtectionTypes []string
+	// ClientToken 保证请求幂等，相同ClientToken的重复请求返回同一个带宽包
+	ClientToken string
+}
+
+// BandwidthPackage 共享带宽包信息
+type BandwidthPackage struct {
+	BandwidthPackageID string
+	Status             string
+	Bandwidth          string
+	InternetChargeType string
+	ISP                string
+	Name               string
+	Description        string
+	ResourceGroupID    string
+	PublicIPAddresses  []BandwidthPackageIP
+}
+
+// BandwidthPackageIP 共享带宽包中的EIP
+type BandwidthPackageIP struct {
+	AllocationID string
+	IPAddress    string
+}
+
+// PublicIPAddressPoolOptions 公网IP地址池创建选项
+type PublicIPAddressPoolOptions struct {
+	ISP             string
+	ResourceGroupID string
+	Name            string
+	Description     string
+	// ClientToken 保证请求幂等，相同ClientToken的重复请求返回同一个地址池
+	ClientToken string
+}
+
+// PublicIPAddressPoolCidrBlock 公网IP地址池中的IP地址段
+type PublicIPAddressPoolCidrBlock struct {
+	CidrBlock  string
+	Status     string
+	TotalIPNum int
+	UsedIPNum  int
+}
+
 const (
+	// DeletionProtectionTypeEIP 删除保护接口中EIP的资源类型
+	DeletionProtectionTypeEIP = "EIP"
+
 	// EIPStatusAvailable EIP可用状态
 	EIPStatusAvailable = "Available"
 	// EIPStatusInUse EIP使用中状态
@@ -81,8 +215,44 @@ const (
 )
 
 const (
+	// EIPAssociatedInstanceTypeEcsInstance 绑定到ECS实例
+	EIPAssociatedInstanceTypeEcsInstance = "EcsInstance"
 	// EIPAssociatedInstanceTypeNetworkInterface 绑定到ENI
 	EIPAssociatedInstanceTypeNetworkInterface = "NetworkInterface"
+	// EIPAssociatedInstanceTypeSlbInstance 绑定到SLB实例
+	EIPAssociatedInstanceTypeSlbInstance = "SlbInstance"
+	// EIPAssociatedInstanceTypeNat 绑定到NAT网关
+	EIPAssociatedInstanceTypeNat = "Nat"
+	// EIPAssociatedInstanceTypeHaVip 绑定到高可用虚拟IP
+	EIPAssociatedInstanceTypeHaVip = "HaVip"
 	// EIPInstanceTypeNetworkInterface 实例类型为ENI
 	EIPInstanceTypeNetworkInterface = "NetworkInterface"
 )
+
+const (
+	// EIPSegmentStatusAllocated 连续EIP地址段已分配
+	EIPSegmentStatusAllocated = "Allocated"
+)
+
+const (
+	// BandwidthPackageStatusAvailable 共享带宽包可用状态
+	BandwidthPackageStatusAvailable = "Available"
+)
+
+// ResourceGroupStatusOK 资源组可用状态
+const ResourceGroupStatusOK = "OK"
+
+// ResourceGroup 资源组信息
+type ResourceGroup struct {
+	ID          string
+	Name        string
+	DisplayName string
+	Status      string
+}
+
+// CallerIdentity 当前凭证对应的身份
+type CallerIdentity struct {
+	AccountID    string
+	Arn          string
+	IdentityType string
+}
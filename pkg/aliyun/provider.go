@@ -0,0 +1,377 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aliyun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/auth/credentials"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+)
+
+const (
+	// DefaultSTSEndpoint 默认的STS接入地址，VPC内无公网时可配置为sts-vpc.<region>.aliyuncs.com
+	DefaultSTSEndpoint = "sts.aliyuncs.com"
+	// DefaultECSMetadataEndpoint ECS实例元数据服务地址
+	DefaultECSMetadataEndpoint = "http://100.100.100.200"
+
+	// RRSA注入到Pod中的环境变量
+	EnvRoleARN         = "ALIBABA_CLOUD_ROLE_ARN"
+	EnvOIDCProviderARN = "ALIBABA_CLOUD_OIDC_PROVIDER_ARN"
+	EnvOIDCTokenFile   = "ALIBABA_CLOUD_OIDC_TOKEN_FILE"
+
+	// credentialRefreshWindow 临时凭证过期前多久开始刷新
+	credentialRefreshWindow = 5 * time.Minute
+	// credentialRetrieveTimeout 单次获取凭证的超时
+	credentialRetrieveTimeout = 10 * time.Second
+	// stsRegionID 调用STS时使用的地域，接入地址由endpoint指定
+	stsRegionID = "cn-hangzhou"
+)
+
+// Credentials 访问阿里云的凭证，临时凭证带有SecurityToken和过期时间
+type Credentials struct {
+	AccessKeyID     string
+	AccessKeySecret string
+	SecurityToken   string
+	// Expiration 过期时间，为零表示长期有效
+	Expiration time.Time
+}
+
+// CredentialProvider 提供访问阿里云的凭证
+type CredentialProvider interface {
+	// Retrieve 返回当前可用的凭证
+	Retrieve(ctx context.Context) (*Credentials, error)
+}
+
+// StaticProvider 长期有效的AccessKey
+type StaticProvider struct {
+	AccessKeyID     string
+	AccessKeySecret string
+}
+
+// Retrieve implements CredentialProvider
+func (p *StaticProvider) Retrieve(ctx context.Context) (*Credentials, error) {
+	if p.AccessKeyID == "" || p.AccessKeySecret == "" {
+		return nil, fmt.Errorf("access key is empty")
+	}
+	return &Credentials{AccessKeyID: p.AccessKeyID, AccessKeySecret: p.AccessKeySecret}, nil
+}
+
+// CachedProvider 缓存临时凭证，过期前credentialRefreshWindow内的第一次调用重新获取
+type CachedProvider struct {
+	provider CredentialProvider
+
+	mu    sync.Mutex
+	creds *Credentials
+}
+
+// NewCachedProvider 为获取临时凭证的provider加上缓存和自动刷新
+func NewCachedProvider(provider CredentialProvider) *CachedProvider {
+	return &CachedProvider{provider: provider}
+}
+
+// Retrieve implements CredentialProvider, a failed refresh keeps using credentials that have not expired yet
+func (p *CachedProvider) Retrieve(ctx context.Context) (*Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.creds != nil && (p.creds.Expiration.IsZero() || now.Before(p.creds.Expiration.Add(-credentialRefreshWindow))) {
+		return p.creds, nil
+	}
+
+	creds, err := p.provider.Retrieve(ctx)
+	if err != nil {
+		if p.creds != nil && now.Before(p.creds.Expiration) {
+			return p.creds, nil
+		}
+		return nil, err
+	}
+	p.creds = creds
+	return creds, nil
+}
+
+// ECSRAMRoleProvider 从ECS实例元数据服务获取实例RAM角色的临时凭证
+type ECSRAMRoleProvider struct {
+	// RoleName 实例RAM角色名称，为空时从元数据服务查询
+	RoleName string
+	// Endpoint 元数据服务地址，默认DefaultECSMetadataEndpoint
+	Endpoint string
+}
+
+// Retrieve implements CredentialProvider
+func (p *ECSRAMRoleProvider) Retrieve(ctx context.Context) (*Credentials, error) {
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultECSMetadataEndpoint
+	}
+	// 加固模式下需要先获取访问元数据的token，未开启加固模式时忽略失败
+	token, _ := p.metadataToken(ctx, endpoint)
+
+	roleName := p.RoleName
+	if roleName == "" {
+		body, err := p.get(ctx, endpoint+"/latest/meta-data/ram/security-credentials/", token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get instance RAM role: %w", err)
+		}
+		roleName = strings.TrimSpace(string(body))
+		if roleName == "" {
+			return nil, fmt.Errorf("no RAM role is attached to the instance")
+		}
+	}
+
+	body, err := p.get(ctx, endpoint+"/latest/meta-data/ram/security-credentials/"+url.PathEscape(roleName), token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials of instance RAM role %s: %w", roleName, err)
+	}
+	var result struct {
+		Code            string `json:"Code"`
+		AccessKeyId     string `json:"AccessKeyId"`
+		AccessKeySecret string `json:"AccessKeySecret"`
+		SecurityToken   string `json:"SecurityToken"`
+		Expiration      string `json:"Expiration"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials of instance RAM role %s: %w", roleName, err)
+	}
+	if result.Code != "Success" {
+		return nil, fmt.Errorf("failed to get credentials of instance RAM role %s: %s", roleName, result.Code)
+	}
+	return toCredentials(result.AccessKeyId, result.AccessKeySecret, result.SecurityToken, result.Expiration)
+}
+
+// metadataToken 获取加固模式下访问元数据服务的token
+func (p *ECSRAMRoleProvider) metadataToken(ctx context.Context, endpoint string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-aliyun-ecs-metadata-token-ttl-seconds", "21600")
+	body, err := doHTTP(req)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func (p *ECSRAMRoleProvider) get(ctx context.Context, u, token string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-aliyun-ecs-metadata-token", token)
+	}
+	return doHTTP(req)
+}
+
+// OIDCProvider 使用RRSA注入的OIDC token调用AssumeRoleWithOIDC获取临时凭证，每次获取时重新读取token文件
+type OIDCProvider struct {
+	RoleARN         string
+	OIDCProviderARN string
+	OIDCTokenFile   string
+	RoleSessionName string
+	// DurationSeconds 临时凭证有效期，为0时使用STS的默认值
+	DurationSeconds int
+	// STSEndpoint 默认DefaultSTSEndpoint
+	STSEndpoint string
+}
+
+// NewOIDCProviderFromEnv 按RRSA注入的环境变量补全未设置的参数
+func NewOIDCProviderFromEnv(p OIDCProvider) *OIDCProvider {
+	if p.RoleARN == "" {
+		p.RoleARN = os.Getenv(EnvRoleARN)
+	}
+	if p.OIDCProviderARN == "" {
+		p.OIDCProviderARN = os.Getenv(EnvOIDCProviderARN)
+	}
+	if p.OIDCTokenFile == "" {
+		p.OIDCTokenFile = os.Getenv(EnvOIDCTokenFile)
+	}
+	return &p
+}
+
+// Retrieve implements CredentialProvider
+func (p *OIDCProvider) Retrieve(ctx context.Context) (*Credentials, error) {
+	if p.RoleARN == "" || p.OIDCProviderARN == "" || p.OIDCTokenFile == "" {
+		return nil, fmt.Errorf("roleARN, oidcProviderARN and oidcTokenFile are required for RRSA")
+	}
+	token, err := os.ReadFile(p.OIDCTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OIDC token: %w", err)
+	}
+
+	// AssumeRoleWithOIDC 是匿名接口，不需要签名
+	form := url.Values{}
+	form.Set("Action", "AssumeRoleWithOIDC")
+	form.Set("Format", "JSON")
+	form.Set("Version", "2015-04-01")
+	form.Set("Timestamp", time.Now().UTC().Format("2006-01-02T15:04:05Z"))
+	form.Set("RoleArn", p.RoleARN)
+	form.Set("OIDCProviderArn", p.OIDCProviderARN)
+	form.Set("OIDCToken", strings.TrimSpace(string(token)))
+	form.Set("RoleSessionName", sessionNameOrDefault(p.RoleSessionName))
+	if p.DurationSeconds > 0 {
+		form.Set("DurationSeconds", strconv.Itoa(p.DurationSeconds))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+stsEndpointOrDefault(p.STSEndpoint)+"/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	body, err := doHTTP(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assume role %s with OIDC: %w", p.RoleARN, err)
+	}
+	return parseSTSCredentials(body)
+}
+
+// AssumeRoleProvider 使用Source提供的凭证扮演RAM角色，可以串联在任意provider之后
+type AssumeRoleProvider struct {
+	Source          CredentialProvider
+	RoleARN         string
+	RoleSessionName string
+	// ExternalID 角色信任策略要求的外部ID，可选
+	ExternalID string
+	// DurationSeconds 临时凭证有效期，为0时使用STS的默认值
+	DurationSeconds int
+	// STSEndpoint 默认DefaultSTSEndpoint
+	STSEndpoint string
+
+	once   sync.Once
+	client *sdk.Client
+	err    error
+}
+
+// Retrieve implements CredentialProvider
+func (p *AssumeRoleProvider) Retrieve(ctx context.Context) (*Credentials, error) {
+	p.once.Do(func() {
+		p.client, p.err = newSDKClient(stsRegionID)
+	})
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	req := requests.NewCommonRequest()
+	req.Method = requests.POST
+	req.Scheme = "https"
+	req.Domain = stsEndpointOrDefault(p.STSEndpoint)
+	req.Product = "Sts"
+	req.Version = "2015-04-01"
+	req.ApiName = "AssumeRole"
+	req.QueryParams["RoleArn"] = p.RoleARN
+	req.QueryParams["RoleSessionName"] = sessionNameOrDefault(p.RoleSessionName)
+	if p.ExternalID != "" {
+		req.QueryParams["ExternalId"] = p.ExternalID
+	}
+	if p.DurationSeconds > 0 {
+		req.QueryParams["DurationSeconds"] = strconv.Itoa(p.DurationSeconds)
+	}
+
+	signer, err := signerFor(ctx, p.Source)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.ProcessCommonRequestWithSigner(req, signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assume role %s: %w", p.RoleARN, err)
+	}
+	return parseSTSCredentials(resp.GetHttpContentBytes())
+}
+
+// newSDKClient 创建SDK客户端，请求需通过signerFor获取的签名器发送
+func newSDKClient(regionID string) (*sdk.Client, error) {
+	// 初始化时的AccessKey只是占位
+	return sdk.NewClientWithOptions(regionID, sdk.NewConfig(), credentials.NewAccessKeyCredential("", ""))
+}
+
+// parseSTSCredentials 解析STS返回的临时凭证
+func parseSTSCredentials(body []byte) (*Credentials, error) {
+	var result struct {
+		Credentials struct {
+			AccessKeyId     string `json:"AccessKeyId"`
+			AccessKeySecret string `json:"AccessKeySecret"`
+			SecurityToken   string `json:"SecurityToken"`
+			Expiration      string `json:"Expiration"`
+		} `json:"Credentials"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse STS credentials: %w", err)
+	}
+	c := result.Credentials
+	return toCredentials(c.AccessKeyId, c.AccessKeySecret, c.SecurityToken, c.Expiration)
+}
+
+func toCredentials(accessKeyID, accessKeySecret, securityToken, expiration string) (*Credentials, error) {
+	if accessKeyID == "" || accessKeySecret == "" {
+		return nil, fmt.Errorf("response contains no credentials")
+	}
+	expires, err := time.Parse(time.RFC3339, expiration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential expiration %q: %w", expiration, err)
+	}
+	return &Credentials{
+		AccessKeyID:     accessKeyID,
+		AccessKeySecret: accessKeySecret,
+		SecurityToken:   securityToken,
+		Expiration:      expires,
+	}, nil
+}
+
+// doHTTP 发送请求，非2xx响应作为错误返回
+func doHTTP(req *http.Request) ([]byte, error) {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Host+req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+func sessionNameOrDefault(name string) string {
+	if name == "" {
+		return DefaultRoleSessionName
+	}
+	return name
+}
+
+func stsEndpointOrDefault(endpoint string) string {
+	if endpoint == "" {
+		return DefaultSTSEndpoint
+	}
+	return endpoint
+}
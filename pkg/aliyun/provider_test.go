@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aliyun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/auth/signers"
+)
+
+// fakeProvider returns the queued results in order
+type fakeProvider struct {
+	results []*Credentials
+	calls   int
+}
+
+func (p *fakeProvider) Retrieve(ctx context.Context) (*Credentials, error) {
+	p.calls++
+	if len(p.results) == 0 {
+		return nil, errors.New("unavailable")
+	}
+	creds := p.results[0]
+	p.results = p.results[1:]
+	return creds, nil
+}
+
+func TestCachedProvider(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		first     *Credentials
+		next      []*Credentials
+		wantID    string
+		wantCalls int
+		wantErr   bool
+	}{
+		{"valid is cached", &Credentials{AccessKeyID: "a", Expiration: now.Add(time.Hour)}, nil, "a", 1, false},
+		{"static is cached", &Credentials{AccessKeyID: "a"}, nil, "a", 1, false},
+		{"refreshed before expiry", &Credentials{AccessKeyID: "a", Expiration: now.Add(time.Minute)},
+			[]*Credentials{{AccessKeyID: "b", Expiration: now.Add(time.Hour)}}, "b", 2, false},
+		{"failed refresh keeps unexpired", &Credentials{AccessKeyID: "a", Expiration: now.Add(time.Minute)}, nil, "a", 2, false},
+		{"failed refresh after expiry", &Credentials{AccessKeyID: "a", Expiration: now.Add(-time.Minute)}, nil, "", 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeProvider{results: append([]*Credentials{tt.first}, tt.next...)}
+			p := NewCachedProvider(fake)
+			if _, err := p.Retrieve(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			creds, err := p.Retrieve(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Retrieve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && creds.AccessKeyID != tt.wantID {
+				t.Errorf("Retrieve() = %s, want %s", creds.AccessKeyID, tt.wantID)
+			}
+			if fake.calls != tt.wantCalls {
+				t.Errorf("provider called %d times, want %d", fake.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// rotatingProvider returns new short lived credentials on every call, so the cache refreshes them constantly
+type rotatingProvider struct {
+	n atomic.Int64
+}
+
+func (p *rotatingProvider) Retrieve(ctx context.Context) (*Credentials, error) {
+	n := p.n.Add(1)
+	return &Credentials{
+		AccessKeyID:     fmt.Sprintf("ak-%d", n),
+		AccessKeySecret: fmt.Sprintf("sk-%d", n),
+		SecurityToken:   fmt.Sprintf("token-%d", n),
+		Expiration:      time.Now().Add(time.Second),
+	}, nil
+}
+
+func TestSignerConcurrentRefresh(t *testing.T) {
+	provider := NewCachedProvider(&rotatingProvider{})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				signer, err := signerFor(context.Background(), provider)
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				// Same order as the SDK's RPC signature composer
+				ak, _ := signer.GetAccessKeyId()
+				token := signer.GetExtraParam()["SecurityToken"]
+				signature := signer.Sign("string-to-sign", "&")
+
+				n := strings.TrimPrefix(ak, "ak-")
+				if token != "token-"+n {
+					errs <- fmt.Errorf("access key %s signed with security token %s", ak, token)
+					return
+				}
+				if want := signers.ShaHmac1("string-to-sign", "sk-"+n+"&"); signature != want {
+					errs <- fmt.Errorf("access key %s signed with another secret", ak)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aliyun
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// RegionalAPI 按地域返回阿里云客户端
+type RegionalAPI interface {
+	// ForRegion 返回指定地域的客户端，regionID为空时返回默认地域的客户端
+	ForRegion(regionID string) (API, error)
+	// DefaultRegion 返回默认地域
+	DefaultRegion() string
+}
+
+// ClientFactory 创建指定地域的客户端
+type ClientFactory func(regionID string) (API, error)
+
+// ClientCache 按地域缓存客户端，地域首次被使用时才创建
+type ClientCache struct {
+	defaultRegion string
+	factory       ClientFactory
+
+	mu      sync.Mutex
+	clients map[string]API
+}
+
+var _ RegionalAPI = &ClientCache{}
+
+// NewClientCache 创建按地域缓存的客户端集合
+func NewClientCache(defaultRegion string, factory ClientFactory) *ClientCache {
+	return &ClientCache{
+		defaultRegion: defaultRegion,
+		factory:       factory,
+		clients:       make(map[string]API),
+	}
+}
+
+// ForRegion 返回指定地域的客户端，创建失败时不缓存，下次调用会重试
+func (c *ClientCache) ForRegion(regionID string) (API, error) {
+	if regionID == "" {
+		regionID = c.defaultRegion
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, ok := c.clients[regionID]; ok {
+		return client, nil
+	}
+	client, err := c.factory(regionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for region %s: %w", regionID, err)
+	}
+	c.clients[regionID] = client
+	return client, nil
+}
+
+// DefaultRegion 返回默认地域
+func (c *ClientCache) DefaultRegion() string {
+	return c.defaultRegion
+}
+
+// Regions 返回已创建客户端的地域
+func (c *ClientCache) Regions() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	regions := make([]string, 0, len(c.clients))
+	for region := range c.clients {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
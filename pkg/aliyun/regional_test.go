@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aliyun
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// regionClient is an API that remembers the region it was created for
+type regionClient struct {
+	API
+	regionID string
+}
+
+func TestClientCache(t *testing.T) {
+	var created []string
+	failing := map[string]bool{"cn-beijing": true}
+	cache := NewClientCache("cn-hangzhou", func(regionID string) (API, error) {
+		created = append(created, regionID)
+		if failing[regionID] {
+			return nil, errors.New("unavailable")
+		}
+		return &regionClient{regionID: regionID}, nil
+	})
+
+	tests := []struct {
+		name       string
+		regionID   string
+		wantRegion string
+		wantErr    bool
+	}{
+		{"empty region uses the default", "", "cn-hangzhou", false},
+		{"default region is cached", "cn-hangzhou", "cn-hangzhou", false},
+		{"other region", "cn-shanghai", "cn-shanghai", false},
+		{"failed region", "cn-beijing", "", true},
+		{"failed region is retried", "cn-beijing", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, err := cache.ForRegion(tt.regionID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ForRegion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && api.(*regionClient).regionID != tt.wantRegion {
+				t.Errorf("ForRegion() region = %s, want %s", api.(*regionClient).regionID, tt.wantRegion)
+			}
+		})
+	}
+
+	if want := []string{"cn-hangzhou", "cn-shanghai", "cn-beijing", "cn-beijing"}; !reflect.DeepEqual(created, want) {
+		t.Errorf("created clients = %v, want %v", created, want)
+	}
+	if want := []string{"cn-hangzhou", "cn-shanghai"}; !reflect.DeepEqual(cache.Regions(), want) {
+		t.Errorf("Regions() = %v, want %v", cache.Regions(), want)
+	}
+	if cache.DefaultRegion() != "cn-hangzhou" {
+		t.Errorf("DefaultRegion() = %s, want cn-hangzhou", cache.DefaultRegion())
+	}
+}
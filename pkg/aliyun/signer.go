@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aliyun
+
+import (
+	"context"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/auth"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/auth/signers"
+)
+
+// credentialsSigner 使用同一份凭证签名一个请求。SDK依次调用GetAccessKeyId、GetExtraParam和Sign，
+// 每个请求单独获取凭证，避免凭证在这几次调用之间刷新导致AccessKey与密钥或SecurityToken不匹配
+type credentialsSigner struct {
+	creds *Credentials
+}
+
+var _ auth.Signer = &credentialsSigner{}
+
+// signerFor 获取本次请求使用的凭证，凭证的缓存和刷新由provider负责
+func signerFor(ctx context.Context, provider CredentialProvider) (auth.Signer, error) {
+	ctx, cancel := context.WithTimeout(ctx, credentialRetrieveTimeout)
+	defer cancel()
+
+	creds, err := provider.Retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &credentialsSigner{creds: creds}, nil
+}
+
+func (*credentialsSigner) GetName() string {
+	return "HMAC-SHA1"
+}
+
+func (*credentialsSigner) GetType() string {
+	return ""
+}
+
+func (*credentialsSigner) GetVersion() string {
+	return "1.0"
+}
+
+func (s *credentialsSigner) GetAccessKeyId() (string, error) {
+	return s.creds.AccessKeyID, nil
+}
+
+func (s *credentialsSigner) GetExtraParam() map[string]string {
+	if s.creds.SecurityToken == "" {
+		return nil
+	}
+	return map[string]string{"SecurityToken": s.creds.SecurityToken}
+}
+
+func (s *credentialsSigner) Sign(stringToSign, secretSuffix string) string {
+	return signers.ShaHmac1(stringToSign, s.creds.AccessKeySecret+secretSuffix)
+}
@@ -19,19 +19,111 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v2"
+
+	"github.com/chrisliu1995/alibabacloud-eip-operator/pkg/limits"
 )
 
 // Config 控制器配置
 type Config struct {
-	RegionID        string   `yaml:"regionID"`
+	RegionID string `yaml:"regionID"`
+	// ClusterID 集群标识，写入EIP的归属标签，多个集群共用同一个阿里云账号时必须配置且互不相同
+	ClusterID       string   `yaml:"clusterID"`
 	VPCID           string   `yaml:"vpcID"`
 	Controllers     []string `yaml:"controllers"`
 	KubeClientQPS   float32  `yaml:"kubeClientQPS"`
 	KubeClientBurst int      `yaml:"kubeClientBurst"`
-	AccessKeyID     string   `yaml:"-"`
-	AccessKeySecret string   `yaml:"-"`
+	// CloudValidation 准入时的云上资源校验
+	CloudValidation CloudValidation `yaml:"cloudValidation"`
+	// BandwidthLimits 带宽限制表，未配置rules时使用内置规则，可按地域覆盖
+	BandwidthLimits *limits.Table `yaml:"bandwidthLimits"`
+	// OrphanGC 孤儿EIP回收
+	OrphanGC OrphanGC `yaml:"orphanGC"`
+	// Credentials 控制器自身凭证的来源
+	Credentials     Credentials `yaml:"credentials"`
+	AccessKeyID     string      `yaml:"-"`
+	AccessKeySecret string      `yaml:"-"`
+}
+
+const (
+	// FailurePolicyIgnore 云上接口调用失败时放行并返回警告
+	FailurePolicyIgnore = "Ignore"
+	// FailurePolicyFail 云上接口调用失败时拒绝请求
+	FailurePolicyFail = "Fail"
+)
+
+// CloudValidation 准入时的云上资源校验配置
+type CloudValidation struct {
+	// Enabled 是否在准入时调用阿里云接口校验引用的资源
+	Enabled bool `yaml:"enabled"`
+	// FailurePolicy 云上接口调用失败时的处理方式，默认Ignore
+	FailurePolicy string `yaml:"failurePolicy"`
+	// CacheTTL 查询结果的缓存时间，默认30s
+	CacheTTL time.Duration `yaml:"cacheTTL"`
+}
+
+// OrphanGC 孤儿EIP回收配置，扫描由operator创建但已没有对应CR的EIP
+type OrphanGC struct {
+	// Enabled 是否启用定期扫描，扫描结果通过事件和指标报告
+	Enabled bool `yaml:"enabled"`
+	// Interval 扫描间隔，默认10m
+	Interval time.Duration `yaml:"interval"`
+	// GracePeriod 孤儿EIP被发现后等待多久才允许释放，默认24h
+	GracePeriod time.Duration `yaml:"gracePeriod"`
+	// Release 超过宽限期后释放孤儿EIP，默认只报告
+	Release bool `yaml:"release"`
+	// DryRun 只报告将被释放的EIP，不调用释放接口
+	DryRun bool `yaml:"dryRun"`
+	// EventNamespace 孤儿EIP事件所在的命名空间，默认alibabacloud-eip-operator-system
+	EventNamespace string `yaml:"eventNamespace"`
+}
+
+const (
+	// CredentialProviderStatic 使用凭证文件中的AccessKey
+	CredentialProviderStatic = "static"
+	// CredentialProviderRRSA 使用RRSA注入的OIDC token扮演RAM角色
+	CredentialProviderRRSA = "rrsa"
+	// CredentialProviderECSRAMRole 使用ECS实例RAM角色
+	CredentialProviderECSRAMRole = "ecsRAMRole"
+)
+
+// Credentials 控制器自身凭证的来源，临时凭证在过期前自动刷新
+type Credentials struct {
+	// Provider 凭证来源：static（默认，读取凭证文件）、rrsa、ecsRAMRole
+	Provider string `yaml:"provider"`
+	// RRSA RRSA参数，未设置的字段读取RRSA注入的ALIBABA_CLOUD_*环境变量
+	RRSA RRSA `yaml:"rrsa"`
+	// ECSRAMRole ECS实例RAM角色参数
+	ECSRAMRole ECSRAMRole `yaml:"ecsRAMRole"`
+	// AssumeRole 在上述凭证的基础上再扮演的RAM角色，可选
+	AssumeRole *AssumeRole `yaml:"assumeRole"`
+	// STSEndpoint STS接入地址，默认sts.aliyuncs.com，无公网时使用sts-vpc.<region>.aliyuncs.com
+	STSEndpoint string `yaml:"stsEndpoint"`
+}
+
+// RRSA 通过OIDC token扮演RAM角色的参数
+type RRSA struct {
+	RoleARN         string `yaml:"roleARN"`
+	OIDCProviderARN string `yaml:"oidcProviderARN"`
+	OIDCTokenFile   string `yaml:"oidcTokenFile"`
+	RoleSessionName string `yaml:"roleSessionName"`
+}
+
+// ECSRAMRole ECS实例RAM角色参数
+type ECSRAMRole struct {
+	// RoleName 实例RAM角色名称，为空时从元数据服务查询
+	RoleName string `yaml:"roleName"`
+}
+
+// AssumeRole 扮演RAM角色的参数
+type AssumeRole struct {
+	RoleARN         string `yaml:"roleARN"`
+	RoleSessionName string `yaml:"roleSessionName"`
+	ExternalID      string `yaml:"externalID"`
+	// DurationSeconds 临时凭证有效期，默认3600
+	DurationSeconds int `yaml:"durationSeconds"`
 }
 
 // Credential 凭证配置
@@ -55,32 +147,47 @@ func ParseAndValidate(configPath, credentialPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
-	// 解析凭证文件
-	credData, err := os.ReadFile(credentialPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read credential file: %w", err)
+	if cfg.Credentials.Provider == "" {
+		cfg.Credentials.Provider = CredentialProviderStatic
 	}
-
-	var cred Credential
-	if err := yaml.Unmarshal(credData, &cred); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal credential: %w", err)
+	switch cfg.Credentials.Provider {
+	case CredentialProviderStatic:
+		// 解析凭证文件，只有static需要长期有效的AccessKey
+		credData, err := os.ReadFile(credentialPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credential file: %w", err)
+		}
+
+		var cred Credential
+		if err := yaml.Unmarshal(credData, &cred); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal credential: %w", err)
+		}
+
+		// 填充凭证信息
+		cfg.AccessKeyID = cred.AccessKeyID
+		cfg.AccessKeySecret = cred.AccessKeySecret
+		if cfg.AccessKeyID == "" {
+			return nil, fmt.Errorf("accessKeyID is required")
+		}
+		if cfg.AccessKeySecret == "" {
+			return nil, fmt.Errorf("accessKeySecret is required")
+		}
+	case CredentialProviderRRSA, CredentialProviderECSRAMRole:
+	default:
+		return nil, fmt.Errorf("credentials.provider must be one of %s, %s, %s",
+			CredentialProviderStatic, CredentialProviderRRSA, CredentialProviderECSRAMRole)
+	}
+	if cfg.Credentials.AssumeRole != nil && cfg.Credentials.AssumeRole.RoleARN == "" {
+		return nil, fmt.Errorf("credentials.assumeRole.roleARN is required")
 	}
-
-	// 填充凭证信息
-	cfg.AccessKeyID = cred.AccessKeyID
-	cfg.AccessKeySecret = cred.AccessKeySecret
 
 	// 验证必填字段
 	if cfg.RegionID == "" {
 		return nil, fmt.Errorf("regionID is required")
 	}
-	if cfg.AccessKeyID == "" {
-		return nil, fmt.Errorf("accessKeyID is required")
-	}
-	if cfg.AccessKeySecret == "" {
-		return nil, fmt.Errorf("accessKeySecret is required")
+	if len(cfg.ClusterID) > 128 {
+		return nil, fmt.Errorf("clusterID must be at most 128 characters")
 	}
-
 	// 设置默认值
 	if cfg.KubeClientQPS == 0 {
 		cfg.KubeClientQPS = 50
@@ -88,11 +195,60 @@ func ParseAndValidate(configPath, credentialPath string) (*Config, error) {
 	if cfg.KubeClientBurst == 0 {
 		cfg.KubeClientBurst = 100
 	}
+	if cfg.CloudValidation.FailurePolicy == "" {
+		cfg.CloudValidation.FailurePolicy = FailurePolicyIgnore
+	}
+	if cfg.CloudValidation.FailurePolicy != FailurePolicyIgnore && cfg.CloudValidation.FailurePolicy != FailurePolicyFail {
+		return nil, fmt.Errorf("cloudValidation.failurePolicy must be %s or %s", FailurePolicyIgnore, FailurePolicyFail)
+	}
+	if cfg.CloudValidation.CacheTTL == 0 {
+		cfg.CloudValidation.CacheTTL = 30 * time.Second
+	}
+	if cfg.CloudValidation.CacheTTL < 0 {
+		return nil, fmt.Errorf("cloudValidation.cacheTTL must be positive")
+	}
+	if cfg.BandwidthLimits, err = limits.Default().Merge(cfg.BandwidthLimits); err != nil {
+		return nil, err
+	}
+	if cfg.OrphanGC.Interval == 0 {
+		cfg.OrphanGC.Interval = 10 * time.Minute
+	}
+	if cfg.OrphanGC.GracePeriod == 0 {
+		cfg.OrphanGC.GracePeriod = 24 * time.Hour
+	}
+	// 扫描间隔为负数时NewTicker会panic
+	if cfg.OrphanGC.Interval < 0 {
+		return nil, fmt.Errorf("orphanGC.interval must be positive")
+	}
+	// 宽限期为负数时刚发现的孤儿EIP会被立即释放
+	if cfg.OrphanGC.GracePeriod < 0 {
+		return nil, fmt.Errorf("orphanGC.gracePeriod must be positive")
+	}
+	// 没有集群标识时无法区分共用账号的其他集群创建的EIP，会把它们当作孤儿释放
+	if cfg.OrphanGC.Enabled && cfg.ClusterID == "" {
+		return nil, fmt.Errorf("clusterID is required when orphanGC is enabled")
+	}
+	if cfg.OrphanGC.EventNamespace == "" {
+		cfg.OrphanGC.EventNamespace = "alibabacloud-eip-operator-system"
+	}
 
 	globalConfig = &cfg
 	return &cfg, nil
 }
 
+// IsControllerEnabled 判断控制器是否启用，未配置controllers或配置"*"时启用全部控制器
+func (c *Config) IsControllerEnabled(name string) bool {
+	if len(c.Controllers) == 0 {
+		return true
+	}
+	for _, controller := range c.Controllers {
+		if controller == "*" || controller == name {
+			return true
+		}
+	}
+	return false
+}
+
 // GetConfig 获取全局配置
 func GetConfig() *Config {
 	return globalConfig
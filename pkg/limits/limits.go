@@ -0,0 +1,249 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package limits 定义EIP的带宽范围以及ISP与计费方式组合的限制表，webhook和控制器共用
+package limits
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	// DefaultVersion 内置限制表的版本
+	DefaultVersion = "2025-02"
+
+	// DefaultISP 未指定ISP时阿里云使用的线路类型
+	DefaultISP = "BGP"
+	// DefaultInternetChargeType 未指定计费方式时阿里云使用的计费方式
+	DefaultInternetChargeType = "PayByTraffic"
+	// DefaultInstanceChargeType 未指定实例计费方式时阿里云使用的实例计费方式
+	DefaultInstanceChargeType = "PostPaid"
+)
+
+var (
+	bgpProISPs     = []string{"BGP_PRO"}
+	singleLineISPs = []string{
+		"ChinaTelecom", "ChinaUnicom", "ChinaMobile",
+		"ChinaTelecom_L2", "ChinaUnicom_L2", "ChinaMobile_L2",
+	}
+)
+
+// Rule 一条限制规则，选择条件为空时匹配任意值
+type Rule struct {
+	ISPs                []string `yaml:"isps,omitempty"`
+	InternetChargeTypes []string `yaml:"internetChargeTypes,omitempty"`
+	InstanceChargeTypes []string `yaml:"instanceChargeTypes,omitempty"`
+
+	// Unsupported 非空表示该组合不被支持，内容为拒绝原因
+	Unsupported string `yaml:"unsupported,omitempty"`
+
+	// MinBandwidth 最小带宽，单位Mbps，0表示不限制
+	MinBandwidth int `yaml:"minBandwidth,omitempty"`
+	// MaxBandwidth 最大带宽，单位Mbps，0表示不限制
+	MaxBandwidth int `yaml:"maxBandwidth,omitempty"`
+}
+
+// Table 带宽限制表，按顺序取第一条匹配的规则
+type Table struct {
+	// Version 限制表版本，便于确认当前生效的规则
+	Version string `yaml:"version"`
+	// Rules 全部地域通用的规则
+	Rules []Rule `yaml:"rules,omitempty"`
+	// RegionOverrides 按地域覆盖的规则，优先于Rules匹配
+	RegionOverrides map[string][]Rule `yaml:"regionOverrides,omitempty"`
+}
+
+// Default 返回内置的限制表
+func Default() *Table {
+	return &Table{
+		Version: DefaultVersion,
+		Rules: []Rule{
+			{
+				ISPs:                singleLineISPs,
+				InstanceChargeTypes: []string{"PrePaid"},
+				Unsupported:         "单线 ISP 不支持包年包月 (PrePaid)，只能使用后付费 (PostPaid)",
+			},
+			{
+				ISPs:                singleLineISPs,
+				InternetChargeTypes: []string{"PayByTraffic"},
+				Unsupported:         "单线 ISP 只支持按固定带宽付费 (PayByBandwidth)，不支持按流量付费 (PayByTraffic)",
+			},
+			{
+				InstanceChargeTypes: []string{"PrePaid"},
+				InternetChargeTypes: []string{"PayByTraffic"},
+				Unsupported:         "当 InstanceChargeType 为 PrePaid（预付费）时，InternetChargeType 必须为 PayByBandwidth",
+			},
+			// BGP_PRO和单线ISP的带宽范围单独列出，其余线路使用下面的通用范围
+			{
+				ISPs:                bgpProISPs,
+				InstanceChargeTypes: []string{"PrePaid"},
+				InternetChargeTypes: []string{"PayByBandwidth"},
+				MinBandwidth:        1,
+				MaxBandwidth:        1000,
+			},
+			{
+				ISPs:                bgpProISPs,
+				InternetChargeTypes: []string{"PayByTraffic"},
+				MinBandwidth:        1,
+				MaxBandwidth:        200,
+			},
+			{
+				ISPs:                bgpProISPs,
+				InternetChargeTypes: []string{"PayByBandwidth"},
+				MinBandwidth:        1,
+				MaxBandwidth:        500,
+			},
+			{
+				ISPs:                singleLineISPs,
+				InternetChargeTypes: []string{"PayByBandwidth"},
+				MinBandwidth:        1,
+				MaxBandwidth:        500,
+			},
+			{
+				InstanceChargeTypes: []string{"PrePaid"},
+				InternetChargeTypes: []string{"PayByBandwidth"},
+				MinBandwidth:        1,
+				MaxBandwidth:        1000,
+			},
+			{
+				InternetChargeTypes: []string{"PayByTraffic"},
+				MinBandwidth:        1,
+				MaxBandwidth:        200,
+			},
+			{
+				InternetChargeTypes: []string{"PayByBandwidth"},
+				MinBandwidth:        1,
+				MaxBandwidth:        500,
+			},
+		},
+	}
+}
+
+// Merge 使用配置中的限制表覆盖内置规则，未配置rules时沿用内置规则，地域覆盖规则总是生效
+func (t *Table) Merge(override *Table) (*Table, error) {
+	if override == nil {
+		return t, nil
+	}
+
+	merged := &Table{
+		Version:         t.Version,
+		Rules:           t.Rules,
+		RegionOverrides: override.RegionOverrides,
+	}
+	if len(override.Rules) > 0 {
+		if override.Version == "" {
+			return nil, fmt.Errorf("bandwidthLimits.version is required when rules are set")
+		}
+		merged.Rules = override.Rules
+	}
+	if override.Version != "" {
+		merged.Version = override.Version
+	}
+	return merged, nil
+}
+
+// ForRegion 返回指定地域生效的限制表，地域覆盖规则排在通用规则之前
+func (t *Table) ForRegion(regionID string) *Table {
+	rules := make([]Rule, 0, len(t.RegionOverrides[regionID])+len(t.Rules))
+	rules = append(rules, t.RegionOverrides[regionID]...)
+	rules = append(rules, t.Rules...)
+	return &Table{Version: t.Version, Rules: rules}
+}
+
+// Violation 不符合限制表的字段
+type Violation struct {
+	// Field 违反限制的字段，为internetChargeType、instanceChargeType或bandwidth
+	Field   string
+	Value   string
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s %q: %s", v.Field, v.Value, v.Message)
+}
+
+// Check 校验ISP、计费方式组合和带宽，bandwidth为空时只校验组合，未指定的ISP和计费方式按阿里云默认值校验
+// 不支持的组合在规则指定了流量计费方式时报告在internetChargeType上，否则报告在instanceChargeType上
+func (t *Table) Check(isp, internetChargeType, instanceChargeType, bandwidth string) *Violation {
+	if isp == "" {
+		isp = DefaultISP
+	}
+	if internetChargeType == "" {
+		internetChargeType = DefaultInternetChargeType
+	}
+	if instanceChargeType == "" {
+		instanceChargeType = DefaultInstanceChargeType
+	}
+
+	value := 0
+	if bandwidth != "" {
+		var err error
+		if value, err = strconv.Atoi(bandwidth); err != nil {
+			return &Violation{Field: "bandwidth", Value: bandwidth, Message: "带宽必须为整数，单位 Mbps"}
+		}
+	}
+
+	rule := t.match(isp, internetChargeType, instanceChargeType)
+	if rule == nil {
+		return nil
+	}
+
+	if rule.Unsupported != "" {
+		if len(rule.InternetChargeTypes) > 0 {
+			return &Violation{Field: "internetChargeType", Value: internetChargeType,
+				Message: fmt.Sprintf("%s (isp %s)", rule.Unsupported, isp)}
+		}
+		return &Violation{Field: "instanceChargeType", Value: instanceChargeType,
+			Message: fmt.Sprintf("%s (isp %s)", rule.Unsupported, isp)}
+	}
+
+	if bandwidth == "" {
+		return nil
+	}
+	if (rule.MinBandwidth > 0 && value < rule.MinBandwidth) || (rule.MaxBandwidth > 0 && value > rule.MaxBandwidth) {
+		return &Violation{Field: "bandwidth", Value: bandwidth,
+			Message: fmt.Sprintf("ISP %s、%s、%s 的带宽范围为 %d-%d Mbps (限制表版本 %s)",
+				isp, internetChargeType, instanceChargeType, rule.MinBandwidth, rule.MaxBandwidth, t.Version)}
+	}
+
+	return nil
+}
+
+// match 返回第一条匹配的规则
+func (t *Table) match(isp, internetChargeType, instanceChargeType string) *Rule {
+	for i := range t.Rules {
+		rule := &t.Rules[i]
+		if matches(rule.ISPs, isp) &&
+			matches(rule.InternetChargeTypes, internetChargeType) &&
+			matches(rule.InstanceChargeTypes, instanceChargeType) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func matches(selector []string, value string) bool {
+	if len(selector) == 0 {
+		return true
+	}
+	for _, s := range selector {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limits
+
+import "testing"
+
+func TestCheck(t *testing.T) {
+	table := Default()
+	override, err := table.Merge(&Table{
+		RegionOverrides: map[string][]Rule{
+			"cn-hongkong": {{InternetChargeTypes: []string{"PayByTraffic"}, MinBandwidth: 1, MaxBandwidth: 100}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name               string
+		table              *Table
+		isp                string
+		internetChargeType string
+		instanceChargeType string
+		bandwidth          string
+		wantField          string
+	}{
+		{"bgp by bandwidth", table, "BGP", "PayByBandwidth", "PostPaid", "100", ""},
+		{"default isp by traffic", table, "", "PayByTraffic", "PostPaid", "200", ""},
+		{"by traffic above max", table, "", "PayByTraffic", "PostPaid", "201", "bandwidth"},
+		{"prepaid above postpaid max", table, "BGP", "PayByBandwidth", "PrePaid", "1000", ""},
+		{"not a number", table, "BGP", "PayByBandwidth", "PostPaid", "10M", "bandwidth"},
+		{"single line by traffic", table, "ChinaTelecom", "PayByTraffic", "PostPaid", "", "internetChargeType"},
+		{"single line prepaid", table, "ChinaUnicom", "PayByBandwidth", "PrePaid", "", "instanceChargeType"},
+		{"prepaid by traffic", table, "BGP", "PayByTraffic", "PrePaid", "", "internetChargeType"},
+		{"bgp pro by bandwidth", table, "BGP_PRO", "PayByBandwidth", "PostPaid", "500", ""},
+		{"bgp pro by bandwidth above max", table, "BGP_PRO", "PayByBandwidth", "PostPaid", "501", "bandwidth"},
+		{"bgp pro by traffic above max", table, "BGP_PRO", "PayByTraffic", "PostPaid", "201", "bandwidth"},
+		{"bgp pro prepaid", table, "BGP_PRO", "PayByBandwidth", "PrePaid", "1000", ""},
+		{"bgp pro prepaid above max", table, "BGP_PRO", "PayByBandwidth", "PrePaid", "1001", "bandwidth"},
+		{"bgp pro prepaid by traffic", table, "BGP_PRO", "PayByTraffic", "PrePaid", "", "internetChargeType"},
+		{"single line by bandwidth", table, "ChinaMobile", "PayByBandwidth", "PostPaid", "500", ""},
+		{"single line above max", table, "ChinaTelecom", "PayByBandwidth", "PostPaid", "501", "bandwidth"},
+		{"single line below min", table, "ChinaUnicom", "PayByBandwidth", "PostPaid", "0", "bandwidth"},
+		{"l2 single line by traffic", table, "ChinaTelecom_L2", "PayByTraffic", "PostPaid", "", "internetChargeType"},
+		{"l2 single line prepaid", table, "ChinaMobile_L2", "PayByBandwidth", "PrePaid", "", "instanceChargeType"},
+		{"l2 single line above max", table, "ChinaUnicom_L2", "PayByBandwidth", "PostPaid", "501", "bandwidth"},
+		{"default charge types above max", table, "", "", "", "201", "bandwidth"},
+		{"default charge types on single line", table, "ChinaTelecom", "", "", "", "internetChargeType"},
+		{"default instance charge type on single line", table, "ChinaMobile", "PayByBandwidth", "", "500", ""},
+		{"region override", override.ForRegion("cn-hongkong"), "", "PayByTraffic", "PostPaid", "150", "bandwidth"},
+		{"other region", override.ForRegion("cn-hangzhou"), "", "PayByTraffic", "PostPaid", "150", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.table.Check(tt.isp, tt.internetChargeType, tt.instanceChargeType, tt.bandwidth)
+			switch {
+			case tt.wantField == "" && v != nil:
+				t.Errorf("unexpected violation: %v", v)
+			case tt.wantField != "" && v == nil:
+				t.Errorf("expected violation on %s", tt.wantField)
+			case tt.wantField != "" && v.Field != tt.wantField:
+				t.Errorf("violation on %s, want %s", v.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestMergeRequiresVersion(t *testing.T) {
+	if _, err := Default().Merge(&Table{Rules: []Rule{{MaxBandwidth: 10}}}); err == nil {
+		t.Error("expected an error for rules without a version")
+	}
+}
@@ -73,7 +73,7 @@ read -p "删除 CRD? (y/n) " -n 1 -r
 echo
 if [[ $REPLY =~ ^[Yy]$ ]]; then
     info "5. 删除 CRD..."
-    kubectl delete -f config/crd/eip.alibabacloud.com_eips.yaml --ignore-not-found=true
+    kubectl delete -k config/crd --ignore-not-found=true
 fi
 
 # 5. 删除 Namespace